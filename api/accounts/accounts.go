@@ -23,6 +23,7 @@ import (
 	"github.com/playmakerchain/powerplay/powerplay"
 	"github.com/playmakerchain/powerplay/runtime"
 	"github.com/playmakerchain/powerplay/state"
+	"github.com/playmakerchain/powerplay/trie"
 	"github.com/playmakerchain/powerplay/tx"
 	"github.com/playmakerchain/powerplay/xenv"
 )
@@ -30,19 +31,41 @@ import (
 type Accounts struct {
 	chain        *chain.Chain
 	stateCreator *state.Creator
+	history      *state.History
 	callGasLimit uint64
 }
 
-func New(chain *chain.Chain, stateCreator *state.Creator, callGasLimit uint64) *Accounts {
+func New(chain *chain.Chain, stateCreator *state.Creator, history *state.History, callGasLimit uint64) *Accounts {
 	return &Accounts{
 		chain,
 		stateCreator,
+		history,
 		callGasLimit,
 	}
 }
 
-func (a *Accounts) getCode(addr powerplay.Address, stateRoot powerplay.Bytes32) ([]byte, error) {
-	state, err := a.stateCreator.NewState(stateRoot)
+// newState creates state of the block.
+// It falls back to state history if the state trie is missing.
+func (a *Accounts) newState(header *block.Header) (*state.State, error) {
+	st, err := a.stateCreator.NewState(header.StateRoot())
+	if err == nil || a.history == nil {
+		return st, err
+	}
+	if _, missing := err.(*trie.MissingNodeError); !missing {
+		return nil, err
+	}
+	st, herr := a.history.NewState(header.ID())
+	if herr != nil {
+		if a.history.IsNotFound(herr) {
+			return nil, err
+		}
+		return nil, herr
+	}
+	return st, nil
+}
+
+func (a *Accounts) getCode(addr powerplay.Address, header *block.Header) ([]byte, error) {
+	state, err := a.newState(header)
 	if err != nil {
 		return nil, err
 	}
@@ -63,7 +86,7 @@ func (a *Accounts) handleGetCode(w http.ResponseWriter, req *http.Request) error
 	if err != nil {
 		return err
 	}
-	code, err := a.getCode(addr, h.StateRoot())
+	code, err := a.getCode(addr, h)
 	if err != nil {
 		return err
 	}
@@ -71,7 +94,7 @@ func (a *Accounts) handleGetCode(w http.ResponseWriter, req *http.Request) error
 }
 
 func (a *Accounts) getAccount(addr powerplay.Address, header *block.Header) (*Account, error) {
-	state, err := a.stateCreator.NewState(header.StateRoot())
+	state, err := a.newState(header)
 	if err != nil {
 		return nil, err
 	}
@@ -88,8 +111,8 @@ func (a *Accounts) getAccount(addr powerplay.Address, header *block.Header) (*Ac
 	}, nil
 }
 
-func (a *Accounts) getStorage(addr powerplay.Address, key powerplay.Bytes32, stateRoot powerplay.Bytes32) (powerplay.Bytes32, error) {
-	state, err := a.stateCreator.NewState(stateRoot)
+func (a *Accounts) getStorage(addr powerplay.Address, key powerplay.Bytes32, header *block.Header) (powerplay.Bytes32, error) {
+	state, err := a.newState(header)
 	if err != nil {
 		return powerplay.Bytes32{}, err
 	}
@@ -129,7 +152,7 @@ func (a *Accounts) handleGetStorage(w http.ResponseWriter, req *http.Request) er
 	if err != nil {
 		return err
 	}
-	storage, err := a.getStorage(addr, key, h.StateRoot())
+	storage, err := a.getStorage(addr, key, h)
 	if err != nil {
 		return err
 	}
@@ -193,7 +216,7 @@ func (a *Accounts) batchCall(ctx context.Context, batchCallData *BatchCallData,
 	if err != nil {
 		return nil, err
 	}
-	state, err := a.stateCreator.NewState(header.StateRoot())
+	state, err := a.newState(header)
 	if err != nil {
 		return nil, err
 	}
@@ -281,6 +304,9 @@ func (a *Accounts) handleRevision(revision string) (*block.Header, error) {
 	if revision == "" || revision == "best" {
 		return a.chain.BestBlock().Header(), nil
 	}
+	if revision == "finalized" {
+		return a.chain.FinalizedBlock(), nil
+	}
 	if len(revision) == 66 || len(revision) == 64 {
 		blockID, err := powerplay.ParseBytes32(revision)
 		if err != nil {
@@ -0,0 +1,59 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package admin
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/api/utils"
+	"github.com/playmakerchain/powerplay/chain"
+)
+
+// Rewinder resets the chain to an earlier trunk block.
+type Rewinder interface {
+	Rewind(num uint32) error
+}
+
+type Admin struct {
+	chain    *chain.Chain
+	rewinder Rewinder
+}
+
+func New(chain *chain.Chain, rewinder Rewinder) *Admin {
+	return &Admin{
+		chain,
+		rewinder,
+	}
+}
+
+func (a *Admin) handleRewind(w http.ResponseWriter, req *http.Request) error {
+	var opt *RewindOption
+	if err := utils.ParseJSON(req.Body, &opt); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	if opt == nil || opt.To == nil {
+		return utils.BadRequest(errors.New("to: required"))
+	}
+	if *opt.To >= a.chain.BestBlock().Header().Number() {
+		return utils.Forbidden(errors.New("to: should be below best block"))
+	}
+	if err := a.rewinder.Rewind(*opt.To); err != nil {
+		return err
+	}
+	best := a.chain.BestBlock().Header()
+	return utils.WriteJSON(w, &BestBlock{
+		ID:     best.ID(),
+		Number: best.Number(),
+	})
+}
+
+func (a *Admin) Mount(root *mux.Router, pathPrefix string) {
+	sub := root.PathPrefix(pathPrefix).Subrouter()
+
+	sub.Path("/rewind").Methods(http.MethodPost).HandlerFunc(utils.WrapHandlerFunc(a.handleRewind))
+}
@@ -0,0 +1,19 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package admin
+
+import (
+	"github.com/playmakerchain/powerplay/powerplay"
+)
+
+type RewindOption struct {
+	To *uint32 `json:"to"`
+}
+
+type BestBlock struct {
+	ID     powerplay.Bytes32 `json:"id"`
+	Number uint32            `json:"number"`
+}
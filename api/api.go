@@ -14,25 +14,45 @@ import (
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 	"github.com/playmakerchain/powerplay/api/accounts"
+	"github.com/playmakerchain/powerplay/api/admin"
 	"github.com/playmakerchain/powerplay/api/blocks"
 	"github.com/playmakerchain/powerplay/api/debug"
 	"github.com/playmakerchain/powerplay/api/doc"
 	"github.com/playmakerchain/powerplay/api/events"
 	"github.com/playmakerchain/powerplay/api/eventslegacy"
+	"github.com/playmakerchain/powerplay/api/evidences"
 	"github.com/playmakerchain/powerplay/api/node"
 	"github.com/playmakerchain/powerplay/api/subscriptions"
 	"github.com/playmakerchain/powerplay/api/transactions"
 	"github.com/playmakerchain/powerplay/api/transfers"
 	"github.com/playmakerchain/powerplay/api/transferslegacy"
 	"github.com/playmakerchain/powerplay/chain"
+	"github.com/playmakerchain/powerplay/equivocation"
 	"github.com/playmakerchain/powerplay/logdb"
+	"github.com/playmakerchain/powerplay/proposers"
 	"github.com/playmakerchain/powerplay/state"
 	"github.com/playmakerchain/powerplay/txpool"
+	"github.com/playmakerchain/powerplay/txtracker"
 )
 
+// Options options of api. Services left nil are not enabled.
+type Options struct {
+	History        *state.History // to query states of blocks in archive mode
+	ProposerIndex  *proposers.Index
+	EvidencePool   *equivocation.Pool
+	Leaser         node.Leaser
+	GasLimiter     node.GasLimiter
+	Producer       node.Producer
+	Rewinder       admin.Rewinder // to enable admin api
+	AllowDryRun    bool           // to serve dry run of block production
+	AllowedOrigins string
+	BacktraceLimit uint32
+	CallGasLimit   uint64
+}
+
 //New return api router
-func New(chain *chain.Chain, stateCreator *state.Creator, txPool *txpool.TxPool, logDB *logdb.LogDB, nw node.Network, allowedOrigins string, backtraceLimit uint32, callGasLimit uint64) (http.HandlerFunc, func()) {
-	origins := strings.Split(strings.TrimSpace(allowedOrigins), ",")
+func New(chain *chain.Chain, stateCreator *state.Creator, txPool *txpool.TxPool, txTracker *txtracker.Tracker, logDB *logdb.LogDB, nw node.Network, opts Options) (http.HandlerFunc, func()) {
+	origins := strings.Split(strings.TrimSpace(opts.AllowedOrigins), ",")
 	for i, o := range origins {
 		origins[i] = strings.ToLower(strings.TrimSpace(o))
 	}
@@ -53,7 +73,7 @@ func New(chain *chain.Chain, stateCreator *state.Creator, txPool *txpool.TxPool,
 			http.Redirect(w, req, "doc/swagger-ui/", http.StatusTemporaryRedirect)
 		})
 
-	accounts.New(chain, stateCreator, callGasLimit).
+	accounts.New(chain, stateCreator, opts.History, opts.CallGasLimit).
 		Mount(router, "/accounts")
 	eventslegacy.New(logDB).
 		Mount(router, "/events")
@@ -69,13 +89,21 @@ func New(chain *chain.Chain, stateCreator *state.Creator, txPool *txpool.TxPool,
 		Mount(router, "/logs/transfer")
 	blocks.New(chain).
 		Mount(router, "/blocks")
-	transactions.New(chain, txPool).
+	transactions.New(chain, txPool, txTracker).
 		Mount(router, "/transactions")
 	debug.New(chain, stateCreator).
 		Mount(router, "/debug")
-	node.New(nw).
+	node.New(nw, opts.ProposerIndex, opts.Leaser, opts.GasLimiter, opts.Producer, opts.AllowDryRun).
 		Mount(router, "/node")
-	subs := subscriptions.New(chain, origins, backtraceLimit)
+	if opts.EvidencePool != nil {
+		evidences.New(opts.EvidencePool).
+			Mount(router, "/evidences")
+	}
+	if opts.Rewinder != nil {
+		admin.New(chain, opts.Rewinder).
+			Mount(router, "/admin")
+	}
+	subs := subscriptions.New(chain, txTracker, origins, opts.BacktraceLimit)
 	subs.Mount(router, "/subscriptions")
 
 	handler := handlers.CompressHandler(router)
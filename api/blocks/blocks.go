@@ -56,6 +56,9 @@ func (b *Blocks) parseRevision(revision string) (interface{}, error) {
 	if revision == "" || revision == "best" {
 		return nil, nil
 	}
+	if revision == "finalized" {
+		return b.chain.FinalizedBlock().ID(), nil
+	}
 	if len(revision) == 66 || len(revision) == 64 {
 		blockID, err := powerplay.ParseBytes32(revision)
 		if err != nil {
@@ -72,6 +72,15 @@ func TestBlock(t *testing.T) {
 	checkBlock(t, blk, rb)
 	assert.Equal(t, http.StatusOK, statusCode)
 
+	// nothing finalized yet, it's genesis
+	res, statusCode = httpGet(t, ts.URL+"/blocks/finalized")
+	if err := json.Unmarshal(res, &rb); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint32(0), rb.Number)
+	assert.Equal(t, blk.Header().ParentID(), rb.ID)
+	assert.Equal(t, http.StatusOK, statusCode)
+
 }
 
 func initBlockServer(t *testing.T) {
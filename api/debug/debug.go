@@ -164,10 +164,10 @@ func (d *Debug) debugStorage(ctx context.Context, contractAddress powerplay.Addr
 	if err != nil {
 		return nil, err
 	}
-	return storageRangeAt(storageTrie, keyStart, maxResult)
+	return storageRangeAt(storageTrie, d.stateC.GetPreimage, keyStart, maxResult)
 }
 
-func storageRangeAt(t *trie.SecureTrie, start []byte, maxResult int) (*StorageRangeResult, error) {
+func storageRangeAt(t *trie.SecureTrie, getPreimage func(powerplay.Bytes32) ([]byte, error), start []byte, maxResult int) (*StorageRangeResult, error) {
 	it := trie.NewIterator(t.NodeIterator(start))
 	result := StorageRangeResult{Storage: StorageMap{}}
 	for i := 0; i < maxResult && it.Next(); i++ {
@@ -177,9 +177,18 @@ func storageRangeAt(t *trie.SecureTrie, start []byte, maxResult int) (*StorageRa
 		}
 		v := powerplay.BytesToBytes32(content)
 		e := StorageEntry{Value: &v}
-		if preimage := t.GetKey(it.Key); preimage != nil {
-			preimage := powerplay.BytesToBytes32(preimage)
-			e.Key = &preimage
+		preimage := t.GetKey(it.Key)
+		if preimage == nil {
+			preimage, _ = getPreimage(powerplay.BytesToBytes32(it.Key))
+		}
+		if preimage != nil {
+			key := powerplay.BytesToBytes32(preimage)
+			e.Key = &key
+			// the key is usually hashed by SHA3, e.g. of mapping
+			if keyPreimage, _ := getPreimage(key); keyPreimage != nil {
+				hex := hexutil.Encode(keyPreimage)
+				e.KeyPreimage = &hex
+			}
 		}
 		result.Storage[powerplay.BytesToBytes32(it.Key).String()] = e
 	}
@@ -217,6 +226,21 @@ func (d *Debug) handleDebugStorage(w http.ResponseWriter, req *http.Request) err
 	return utils.WriteJSON(w, res)
 }
 
+func (d *Debug) handleGetPreimage(w http.ResponseWriter, req *http.Request) error {
+	hash, err := powerplay.ParseBytes32(mux.Vars(req)["hash"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "hash"))
+	}
+	preimage, err := d.stateC.GetPreimage(hash)
+	if err != nil {
+		return err
+	}
+	if preimage == nil {
+		return utils.WriteJSON(w, nil)
+	}
+	return utils.WriteJSON(w, map[string]string{"preimage": hexutil.Encode(preimage)})
+}
+
 func (d *Debug) parseTarget(target string) (blockID powerplay.Bytes32, txIndex uint64, clauseIndex uint64, err error) {
 	parts := strings.Split(target, "/")
 	if len(parts) != 3 {
@@ -258,5 +282,6 @@ func (d *Debug) Mount(root *mux.Router, pathPrefix string) {
 
 	sub.Path("/tracers").Methods(http.MethodPost).HandlerFunc(utils.WrapHandlerFunc(d.handleTraceTransaction))
 	sub.Path("/storage-range").Methods(http.MethodPost).HandlerFunc(utils.WrapHandlerFunc(d.handleDebugStorage))
+	sub.Path("/preimages/{hash}").Methods(http.MethodGet).HandlerFunc(utils.WrapHandlerFunc(d.handleGetPreimage))
 
 }
@@ -88,6 +88,7 @@ type StorageRangeResult struct {
 type StorageMap map[string]StorageEntry
 
 type StorageEntry struct {
-	Key   *powerplay.Bytes32 `json:"key"`
-	Value *powerplay.Bytes32 `json:"value"`
+	Key         *powerplay.Bytes32 `json:"key"`
+	Value       *powerplay.Bytes32 `json:"value"`
+	KeyPreimage *string            `json:"keyPreimage,omitempty"` // SHA3 input of key, if recorded
 }
@@ -0,0 +1,68 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package evidences
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/api/utils"
+	"github.com/playmakerchain/powerplay/equivocation"
+	"github.com/playmakerchain/powerplay/powerplay"
+)
+
+// Evidences serves equivocation evidences collected by the node.
+type Evidences struct {
+	pool *equivocation.Pool
+}
+
+// New create evidences API.
+func New(pool *equivocation.Pool) *Evidences {
+	return &Evidences{pool}
+}
+
+func (e *Evidences) handleGetEvidences(w http.ResponseWriter, req *http.Request) error {
+	evs, err := e.pool.All()
+	if err != nil {
+		return err
+	}
+	result := make([]*Evidence, 0, len(evs))
+	for _, ev := range evs {
+		converted, err := convertEvidence(ev)
+		if err != nil {
+			return err
+		}
+		result = append(result, converted)
+	}
+	return utils.WriteJSON(w, result)
+}
+
+func (e *Evidences) handleGetEvidence(w http.ResponseWriter, req *http.Request) error {
+	id, err := powerplay.ParseBytes32(mux.Vars(req)["id"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "id"))
+	}
+	ev, err := e.pool.Get(id)
+	if err != nil {
+		if e.pool.IsNotFound(err) {
+			return utils.WriteJSON(w, nil)
+		}
+		return err
+	}
+	converted, err := convertEvidence(ev)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, converted)
+}
+
+func (e *Evidences) Mount(root *mux.Router, pathPrefix string) {
+	sub := root.PathPrefix(pathPrefix).Subrouter()
+
+	sub.Path("").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(e.handleGetEvidences))
+	sub.Path("/{id}").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(e.handleGetEvidence))
+}
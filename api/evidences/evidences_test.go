@@ -0,0 +1,78 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package evidences_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+	"github.com/playmakerchain/powerplay/api/evidences"
+	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/builtin"
+	"github.com/playmakerchain/powerplay/equivocation"
+	"github.com/playmakerchain/powerplay/lvldb"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvidences(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	pool := equivocation.NewPool(db)
+
+	key, _ := crypto.GenerateKey()
+	parent := powerplay.BytesToBytes32([]byte("parent"))
+	var headers []*block.Header
+	for _, ts := range []uint64{10, 20} {
+		b := new(block.Builder).ParentID(parent).Timestamp(ts).Build()
+		sig, _ := crypto.Sign(b.Header().SigningHash().Bytes(), key)
+		headers = append(headers, b.WithSignature(sig).Header())
+	}
+	ev := equivocation.NewEvidence(headers[0], headers[1])
+	_, err := pool.Add(ev)
+	assert.Nil(t, err)
+
+	router := mux.NewRouter()
+	evidences.New(pool).Mount(router, "/evidences")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	var all []*evidences.Evidence
+	httpGetJSON(t, ts.URL+"/evidences", &all)
+	assert.Equal(t, 1, len(all))
+	assert.Equal(t, ev.ID(), all[0].ID)
+	assert.Equal(t, powerplay.Address(crypto.PubkeyToAddress(key.PublicKey)), all[0].Offender)
+	assert.Equal(t, 2, len(all[0].Headers))
+	assert.Equal(t, builtin.Authority.Address, *all[0].RevokeClause.To)
+
+	var one *evidences.Evidence
+	httpGetJSON(t, ts.URL+"/evidences/"+ev.ID().String(), &one)
+	assert.Equal(t, ev.ID(), one.ID)
+
+	one = nil
+	httpGetJSON(t, ts.URL+"/evidences/"+powerplay.Bytes32{}.String(), &one)
+	assert.Nil(t, one)
+}
+
+func httpGetJSON(t *testing.T, url string, result interface{}) {
+	res, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	if err := json.Unmarshal(data, result); err != nil {
+		t.Fatal(err)
+	}
+}
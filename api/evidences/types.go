@@ -0,0 +1,79 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package evidences
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/playmakerchain/powerplay/api/transactions"
+	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/builtin"
+	"github.com/playmakerchain/powerplay/equivocation"
+	"github.com/playmakerchain/powerplay/powerplay"
+)
+
+// Header a signed header in evidence.
+type Header struct {
+	ID        powerplay.Bytes32 `json:"id"`
+	Number    uint32            `json:"number"`
+	ParentID  powerplay.Bytes32 `json:"parentID"`
+	Timestamp uint64            `json:"timestamp"`
+	Raw       string            `json:"raw"` // hex encoded rlp of the header
+}
+
+// Evidence proves a node master signed two conflicting blocks.
+type Evidence struct {
+	ID       powerplay.Bytes32 `json:"id"`
+	Offender powerplay.Address `json:"offender"`
+	Headers  []*Header         `json:"headers"`
+	// clause to revoke the offender, to be proposed via executor
+	RevokeClause *transactions.Clause `json:"revokeClause"`
+}
+
+func convertHeader(header *block.Header) (*Header, error) {
+	raw, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	return &Header{
+		ID:        header.ID(),
+		Number:    header.Number(),
+		ParentID:  header.ParentID(),
+		Timestamp: header.Timestamp(),
+		Raw:       hexutil.Encode(raw),
+	}, nil
+}
+
+func convertEvidence(ev *equivocation.Evidence) (*Evidence, error) {
+	offender, err := ev.Offender()
+	if err != nil {
+		return nil, err
+	}
+	a, err := convertHeader(ev.A)
+	if err != nil {
+		return nil, err
+	}
+	b, err := convertHeader(ev.B)
+	if err != nil {
+		return nil, err
+	}
+
+	method, _ := builtin.Authority.ABI.MethodByName("revoke")
+	data, err := method.EncodeInput(offender)
+	if err != nil {
+		return nil, err
+	}
+	to := builtin.Authority.Address
+	return &Evidence{
+		ID:       ev.ID(),
+		Offender: offender,
+		Headers:  []*Header{a, b},
+		RevokeClause: &transactions.Clause{
+			To:   &to,
+			Data: hexutil.Encode(data),
+		},
+	}, nil
+}
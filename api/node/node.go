@@ -8,18 +8,44 @@ package node
 
 import (
 	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
 	"github.com/playmakerchain/powerplay/api/utils"
+	"github.com/playmakerchain/powerplay/proposers"
+)
+
+const (
+	// default time window of proposer stats
+	defaultProposerWindow = 24 * time.Hour
+
+	defaultProposerSlots = 5
+	maxProposerSlots     = 100
 )
 
 type Node struct {
-	nw Network
+	nw            Network
+	proposerIndex *proposers.Index
+	leaser        Leaser
+	gasLimiter    GasLimiter
+	producer      Producer
+	allowDryRun   bool
+	dryRunning    int32 // 1 while a dry run is in progress, to run one at a time
 }
 
-func New(nw Network) *Node {
+// New create node API. proposerIndex, leaser, gasLimiter and producer are optional.
+// Dry run of block production is expensive, and served only if allowDryRun is set.
+func New(nw Network, proposerIndex *proposers.Index, leaser Leaser, gasLimiter GasLimiter, producer Producer, allowDryRun bool) *Node {
 	return &Node{
-		nw,
+		nw:            nw,
+		proposerIndex: proposerIndex,
+		leaser:        leaser,
+		gasLimiter:    gasLimiter,
+		producer:      producer,
+		allowDryRun:   allowDryRun,
 	}
 }
 
@@ -31,8 +57,99 @@ func (n *Node) handleNetwork(w http.ResponseWriter, req *http.Request) error {
 	return utils.WriteJSON(w, n.PeersStats())
 }
 
+func (n *Node) handleProposers(w http.ResponseWriter, req *http.Request) error {
+	if n.proposerIndex == nil {
+		return utils.Forbidden(errors.New("proposer index not enabled"))
+	}
+	to := uint64(time.Now().Unix())
+	if s := req.URL.Query().Get("to"); s != "" {
+		v, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "to"))
+		}
+		to = v
+	}
+	var from uint64
+	if to > uint64(defaultProposerWindow/time.Second) {
+		from = to - uint64(defaultProposerWindow/time.Second)
+	}
+	if s := req.URL.Query().Get("from"); s != "" {
+		v, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "from"))
+		}
+		from = v
+	}
+	if from > to {
+		return utils.BadRequest(errors.New("from: greater than to"))
+	}
+
+	stats, err := n.proposerIndex.Stats(from, to)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, ConvertProposerStats(from, to, stats))
+}
+
+// handleLease serves as the heartbeat watched by standby nodes.
+func (n *Node) handleLease(w http.ResponseWriter, req *http.Request) error {
+	if n.leaser == nil {
+		return utils.Forbidden(errors.New("not a block producing node"))
+	}
+	held, signed := n.leaser.LeaseStatus()
+	return utils.WriteJSON(w, &Lease{held, signed})
+}
+
+func (n *Node) handleGasLimit(w http.ResponseWriter, req *http.Request) error {
+	if n.gasLimiter == nil {
+		return utils.Forbidden(errors.New("not a block producing node"))
+	}
+	return utils.WriteJSON(w, ConvertGasLimitStatus(n.gasLimiter.GasLimitStatus()))
+}
+
+func (n *Node) handleProposerStatus(w http.ResponseWriter, req *http.Request) error {
+	if n.producer == nil {
+		return utils.Forbidden(errors.New("not a block producing node"))
+	}
+	slots := defaultProposerSlots
+	if s := req.URL.Query().Get("slots"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 0 || v > maxProposerSlots {
+			return utils.BadRequest(errors.New("slots: invalid or out of range"))
+		}
+		slots = v
+	}
+	var dryRun bool
+	if s := req.URL.Query().Get("dryRun"); s != "" {
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "dryRun"))
+		}
+		dryRun = v
+	}
+	if dryRun {
+		if !n.allowDryRun {
+			return utils.Forbidden(errors.New("dry run not enabled"))
+		}
+		if !atomic.CompareAndSwapInt32(&n.dryRunning, 0, 1) {
+			return utils.HTTPError(errors.New("dry run in progress"), http.StatusTooManyRequests)
+		}
+		defer atomic.StoreInt32(&n.dryRunning, 0)
+	}
+
+	status, result, err := n.producer.ProposerStatus(slots, dryRun)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, ConvertProposerStatus(status, result))
+}
+
 func (n *Node) Mount(root *mux.Router, pathPrefix string) {
 	sub := root.PathPrefix(pathPrefix).Subrouter()
 
 	sub.Path("/network/peers").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(n.handleNetwork))
+	sub.Path("/proposers").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(n.handleProposers))
+	sub.Path("/lease").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(n.handleLease))
+	sub.Path("/gas-limit").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(n.handleGasLimit))
+	sub.Path("/proposer").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(n.handleProposerStatus))
 }
@@ -8,6 +8,7 @@ package node_test
 import (
 	"encoding/json"
 	"io/ioutil"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -20,6 +21,7 @@ import (
 	"github.com/playmakerchain/powerplay/comm"
 	"github.com/playmakerchain/powerplay/genesis"
 	"github.com/playmakerchain/powerplay/lvldb"
+	"github.com/playmakerchain/powerplay/packer"
 	"github.com/playmakerchain/powerplay/state"
 	"github.com/playmakerchain/powerplay/txpool"
 )
@@ -36,6 +38,123 @@ func TestNode(t *testing.T) {
 	assert.Equal(t, 0, len(peersStats), "count should be zero")
 }
 
+type gasLimiter struct{}
+
+func (gasLimiter) GasLimitStatus() (bool, packer.GasLimitStatus) {
+	return true, packer.GasLimitStatus{Target: 20000000, Min: 1000000, Elapsed: 1500 * time.Millisecond, Backlog: 3}
+}
+
+func TestGasLimit(t *testing.T) {
+	router := mux.NewRouter()
+	node.New(nil, nil, nil, gasLimiter{}, nil, false).Mount(router, "/node")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	var gl node.GasLimit
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/node/gas-limit"), &gl); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, node.GasLimit{Adaptive: true, Target: 20000000, Min: 1000000, Elapsed: 1500, Backlog: 3}, gl)
+}
+
+type producer struct{}
+
+func (producer) ProposerStatus(slots int, dryRun bool) (*packer.ProposerStatus, *packer.DryRunResult, error) {
+	status := &packer.ProposerStatus{
+		Listed:          true,
+		Candidate:       true,
+		EndorsorBalance: big.NewInt(100),
+		Endorsement:     big.NewInt(10),
+	}
+	for i := 0; i < slots; i++ {
+		status.Slots = append(status.Slots, uint64(10*(i+1)))
+	}
+	if !dryRun {
+		return status, nil, nil
+	}
+	return status, &packer.DryRunResult{Number: 1, TxCount: 2}, nil
+}
+
+func TestProposerStatus(t *testing.T) {
+	router := mux.NewRouter()
+	node.New(nil, nil, nil, nil, producer{}, true).Mount(router, "/node")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	var status node.ProposerStatus
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/node/proposer"), &status); err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, status.Listed)
+	assert.Equal(t, []uint64{10, 20, 30, 40, 50}, status.Slots)
+	assert.Equal(t, big.NewInt(100), (*big.Int)(status.EndorsorBalance))
+	assert.Nil(t, status.DryRun)
+
+	status = node.ProposerStatus{}
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/node/proposer?slots=2&dryRun=true"), &status); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []uint64{10, 20}, status.Slots)
+	assert.Equal(t, &node.DryRun{Number: 1, TxCount: 2}, status.DryRun)
+
+	res, err := http.Get(ts.URL + "/node/proposer?slots=1000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+// blockingProducer blocks dry runs until released.
+type blockingProducer struct {
+	producer
+	started chan struct{}
+	release chan struct{}
+}
+
+func (p blockingProducer) ProposerStatus(slots int, dryRun bool) (*packer.ProposerStatus, *packer.DryRunResult, error) {
+	if dryRun {
+		p.started <- struct{}{}
+		<-p.release
+	}
+	return p.producer.ProposerStatus(slots, dryRun)
+}
+
+func TestProposerDryRunLimited(t *testing.T) {
+	statusCode := func(url string) int {
+		res, err := http.Get(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		return res.StatusCode
+	}
+
+	router := mux.NewRouter()
+	node.New(nil, nil, nil, nil, producer{}, false).Mount(router, "/node")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	assert.Equal(t, http.StatusOK, statusCode(ts.URL+"/node/proposer"))
+	assert.Equal(t, http.StatusForbidden, statusCode(ts.URL+"/node/proposer?dryRun=true"), "not enabled")
+
+	p := blockingProducer{started: make(chan struct{}), release: make(chan struct{})}
+	router = mux.NewRouter()
+	node.New(nil, nil, nil, nil, p, true).Mount(router, "/node")
+	ts2 := httptest.NewServer(router)
+	defer ts2.Close()
+
+	done := make(chan int)
+	go func() { done <- statusCode(ts2.URL + "/node/proposer?dryRun=true") }()
+	<-p.started
+
+	assert.Equal(t, http.StatusTooManyRequests, statusCode(ts2.URL+"/node/proposer?dryRun=true"), "one at a time")
+	assert.Equal(t, http.StatusOK, statusCode(ts2.URL+"/node/proposer"), "status not limited")
+
+	close(p.release)
+	assert.Equal(t, http.StatusOK, <-done)
+}
+
 func initCommServer(t *testing.T) {
 	db, _ := lvldb.NewMem()
 	stateC := state.NewCreator(db)
@@ -46,13 +165,13 @@ func initCommServer(t *testing.T) {
 		t.Fatal(err)
 	}
 	chain, _ := chain.New(db, b)
-	comm := comm.New(chain, txpool.New(chain, stateC, txpool.Options{
+	comm := comm.New(chain, stateC, txpool.New(chain, stateC, txpool.Options{
 		Limit:           10000,
 		LimitPerAccount: 16,
 		MaxLifetime:     10 * time.Minute,
 	}))
 	router := mux.NewRouter()
-	node.New(comm).Mount(router, "/node")
+	node.New(comm, nil, nil, nil, nil, false).Mount(router, "/node")
 	ts = httptest.NewServer(router)
 }
 
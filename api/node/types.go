@@ -7,14 +7,34 @@
 package node
 
 import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/playmakerchain/powerplay/comm"
+	"github.com/playmakerchain/powerplay/packer"
 	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/proposers"
 )
 
 type Network interface {
 	PeersStats() []*comm.PeerStats
 }
 
+// Leaser provides status of the signing lease, shared by primary and standby nodes.
+type Leaser interface {
+	LeaseStatus() (held bool, signed uint32)
+}
+
+// GasLimiter provides status of the target block gas limit.
+type GasLimiter interface {
+	GasLimitStatus() (adaptive bool, status packer.GasLimitStatus)
+}
+
+// Producer reports block production status of the node master.
+type Producer interface {
+	ProposerStatus(slots int, dryRun bool) (*packer.ProposerStatus, *packer.DryRunResult, error)
+}
+
 type PeerStats struct {
 	Name        string       		`json:"name"`
 	BestBlockID powerplay.Bytes32 	`json:"bestBlockID"`
@@ -43,3 +63,124 @@ func ConvertPeersStats(ss []*comm.PeerStats) []*PeerStats {
 	}
 	return peersStats
 }
+
+type Transition struct {
+	Number    uint32 `json:"number"`
+	Timestamp uint64 `json:"timestamp"`
+	Active    bool   `json:"active"`
+}
+
+type ProposerStat struct {
+	Master      powerplay.Address `json:"master"`
+	Produced    uint64            `json:"produced"`
+	Missed      uint64            `json:"missed"`
+	Transitions []Transition      `json:"transitions"`
+}
+
+type ProposerStats struct {
+	From      uint64          `json:"from"`
+	To        uint64          `json:"to"`
+	Proposers []*ProposerStat `json:"proposers"`
+}
+
+func ConvertProposerStats(from, to uint64, ss []*proposers.Stat) *ProposerStats {
+	stats := &ProposerStats{
+		From:      from,
+		To:        to,
+		Proposers: make([]*ProposerStat, 0, len(ss)),
+	}
+	for _, s := range ss {
+		transitions := make([]Transition, 0, len(s.Transitions))
+		for _, t := range s.Transitions {
+			transitions = append(transitions, Transition{t.Number, t.Timestamp, t.Active})
+		}
+		stats.Proposers = append(stats.Proposers, &ProposerStat{
+			Master:      s.Master,
+			Produced:    s.Produced,
+			Missed:      s.Missed,
+			Transitions: transitions,
+		})
+	}
+	return stats
+}
+
+type Lease struct {
+	Held   bool   `json:"held"`
+	Signed uint32 `json:"signed"`
+}
+
+type GasLimit struct {
+	Adaptive bool   `json:"adaptive"`
+	Target   uint64 `json:"target"`
+	Min      uint64 `json:"min"`
+	Max      uint64 `json:"max"`
+	Capacity uint64 `json:"capacity"`
+	GasUsed  uint64 `json:"gasUsed"`
+	GasLimit uint64 `json:"gasLimit"`
+	Elapsed  uint64 `json:"elapsed"` // in milliseconds
+	Backlog  int    `json:"backlog"`
+}
+
+func ConvertGasLimitStatus(adaptive bool, s packer.GasLimitStatus) *GasLimit {
+	return &GasLimit{
+		Adaptive: adaptive,
+		Target:   s.Target,
+		Min:      s.Min,
+		Max:      s.Max,
+		Capacity: s.Capacity,
+		GasUsed:  s.GasUsed,
+		GasLimit: s.GasLimit,
+		Elapsed:  uint64(s.Elapsed / time.Millisecond),
+		Backlog:  s.Backlog,
+	}
+}
+
+type DryRun struct {
+	Number    uint32            `json:"number"`
+	Timestamp uint64            `json:"timestamp"`
+	GasLimit  uint64            `json:"gasLimit"`
+	GasUsed   uint64            `json:"gasUsed"`
+	TxCount   int               `json:"txCount"`
+	StateRoot powerplay.Bytes32 `json:"stateRoot"`
+	Elapsed   uint64            `json:"elapsed"` // in milliseconds
+}
+
+type ProposerStatus struct {
+	Master          powerplay.Address     `json:"master"`
+	Listed          bool                  `json:"listed"`
+	Active          bool                  `json:"active"`
+	Endorsor        powerplay.Address     `json:"endorsor"`
+	EndorsorBalance *math.HexOrDecimal256 `json:"endorsorBalance"`
+	Endorsement     *math.HexOrDecimal256 `json:"endorsement"`
+	Candidate       bool                  `json:"candidate"`
+	Slots           []uint64              `json:"slots"`
+	DryRun          *DryRun               `json:"dryRun,omitempty"`
+}
+
+func ConvertProposerStatus(s *packer.ProposerStatus, r *packer.DryRunResult) *ProposerStatus {
+	status := &ProposerStatus{
+		Master:          s.Master,
+		Listed:          s.Listed,
+		Active:          s.Active,
+		Endorsor:        s.Endorsor,
+		EndorsorBalance: (*math.HexOrDecimal256)(s.EndorsorBalance),
+		Endorsement:     (*math.HexOrDecimal256)(s.Endorsement),
+		Candidate:       s.Candidate,
+		Slots:           s.Slots,
+	}
+	if status.Slots == nil {
+		status.Slots = []uint64{}
+	}
+	if r != nil {
+		status.DryRun = &DryRun{
+			Number:    r.Number,
+			Timestamp: r.Timestamp,
+			GasLimit:  r.GasLimit,
+			GasUsed:   r.GasUsed,
+			TxCount:   r.TxCount,
+			StateRoot: r.StateRoot,
+			Elapsed:   uint64(r.Elapsed / time.Millisecond),
+		}
+	}
+	return status
+}
@@ -18,11 +18,13 @@ import (
 	"github.com/playmakerchain/powerplay/block"
 	"github.com/playmakerchain/powerplay/chain"
 	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/txtracker"
 )
 
 type Subscriptions struct {
 	backtraceLimit uint32
 	chain          *chain.Chain
+	tracker        *txtracker.Tracker
 	upgrader       *websocket.Upgrader
 	done           chan struct{}
 	wg             sync.WaitGroup
@@ -32,14 +34,20 @@ type msgReader interface {
 	Read() (msgs []interface{}, hasMore bool, err error)
 }
 
+// waker is implemented by readers which are fed by push, to wake up the pipe without waiting for new block.
+type waker interface {
+	Wake() <-chan struct{}
+}
+
 var (
 	log = log15.New("pkg", "subscriptions")
 )
 
-func New(chain *chain.Chain, allowedOrigins []string, backtraceLimit uint32) *Subscriptions {
+func New(chain *chain.Chain, tracker *txtracker.Tracker, allowedOrigins []string, backtraceLimit uint32) *Subscriptions {
 	return &Subscriptions{
 		backtraceLimit: backtraceLimit,
 		chain:          chain,
+		tracker:        tracker,
 		upgrader: &websocket.Upgrader{
 			EnableCompression: true,
 			CheckOrigin: func(r *http.Request) bool {
@@ -140,6 +148,21 @@ func (s *Subscriptions) handleBeatReader(w http.ResponseWriter, req *http.Reques
 	return newBeatReader(s.chain, position), nil
 }
 
+func (s *Subscriptions) handleTxStatusReader(w http.ResponseWriter, req *http.Request) (*txStatusReader, error) {
+	if s.tracker == nil {
+		return nil, utils.Forbidden(errors.New("tx tracking disabled"))
+	}
+	var txID *powerplay.Bytes32
+	if id := req.URL.Query().Get("id"); id != "" {
+		parsed, err := powerplay.ParseBytes32(id)
+		if err != nil {
+			return nil, utils.BadRequest(errors.WithMessage(err, "id"))
+		}
+		txID = &parsed
+	}
+	return newTxStatusReader(s.tracker, txID), nil
+}
+
 func (s *Subscriptions) handleSubject(w http.ResponseWriter, req *http.Request) error {
 	s.wg.Add(1)
 	defer s.wg.Done()
@@ -165,6 +188,13 @@ func (s *Subscriptions) handleSubject(w http.ResponseWriter, req *http.Request)
 		if reader, err = s.handleBeatReader(w, req); err != nil {
 			return err
 		}
+	case "txstatus":
+		statusReader, err := s.handleTxStatusReader(w, req)
+		if err != nil {
+			return err
+		}
+		defer statusReader.Close()
+		reader = statusReader
 	default:
 		return utils.HTTPError(errors.New("not found"), http.StatusNotFound)
 	}
@@ -209,6 +239,10 @@ func (s *Subscriptions) pipe(conn *websocket.Conn, reader msgReader) error {
 			}
 		}
 	}()
+	var wake <-chan struct{}
+	if w, ok := reader.(waker); ok {
+		wake = w.Wake()
+	}
 	ticker := s.chain.NewTicker()
 	for {
 		msgs, hasMore, err := reader.Read()
@@ -227,6 +261,7 @@ func (s *Subscriptions) pipe(conn *websocket.Conn, reader msgReader) error {
 			case <-closed:
 				return nil
 			case <-ticker.C():
+			case <-wake:
 			}
 		} else {
 			select {
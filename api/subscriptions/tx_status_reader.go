@@ -0,0 +1,78 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/txtracker"
+)
+
+// txStatusReader reads status changes of tracked txs.
+// Unlike other readers, changes are pushed by the tracker, and buffered until read.
+type txStatusReader struct {
+	txID   *powerplay.Bytes32
+	sub    event.Subscription
+	wake   chan struct{}
+	lock   sync.Mutex
+	buffer []interface{}
+}
+
+func newTxStatusReader(tracker *txtracker.Tracker, txID *powerplay.Bytes32) *txStatusReader {
+	ch := make(chan *txtracker.Change, 100)
+	r := &txStatusReader{
+		txID: txID,
+		sub:  tracker.Subscribe(ch),
+		wake: make(chan struct{}, 1),
+	}
+	if txID != nil {
+		// the current status goes first
+		if change := tracker.Get(*txID); change != nil {
+			r.buffer = append(r.buffer, convertTxStatus(change))
+		}
+	}
+	go func() {
+		for {
+			select {
+			case change := <-ch:
+				if r.txID != nil && *r.txID != change.TxID {
+					continue
+				}
+				r.lock.Lock()
+				r.buffer = append(r.buffer, convertTxStatus(change))
+				r.lock.Unlock()
+				select {
+				case r.wake <- struct{}{}:
+				default:
+				}
+			case <-r.sub.Err():
+				return
+			}
+		}
+	}()
+	return r
+}
+
+func (r *txStatusReader) Read() ([]interface{}, bool, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	msgs := r.buffer
+	r.buffer = nil
+	return msgs, false, nil
+}
+
+// Wake returns the channel signaled when new changes buffered.
+func (r *txStatusReader) Wake() <-chan struct{} {
+	return r.wake
+}
+
+func (r *txStatusReader) Close() error {
+	r.sub.Unsubscribe()
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/playmakerchain/powerplay/chain"
 	"github.com/playmakerchain/powerplay/powerplay"
 	"github.com/playmakerchain/powerplay/tx"
+	"github.com/playmakerchain/powerplay/txtracker"
 )
 
 //BlockMessage block piped by websocket
@@ -200,3 +201,20 @@ type BeatMessage struct {
 	K         uint32       		`json:"k"`
 	Obsolete  bool         		`json:"obsolete"`
 }
+
+// TxStatusMessage status change of tracked tx piped by websocket
+type TxStatusMessage struct {
+	ID         powerplay.Bytes32  `json:"id"`
+	Status     string             `json:"status"`
+	BlockID    *powerplay.Bytes32 `json:"blockID"`
+	ReplacedBy *powerplay.Bytes32 `json:"replacedBy"`
+}
+
+func convertTxStatus(c *txtracker.Change) *TxStatusMessage {
+	return &TxStatusMessage{
+		ID:         c.TxID,
+		Status:     string(c.Status),
+		BlockID:    c.BlockID,
+		ReplacedBy: c.ReplacedBy,
+	}
+}
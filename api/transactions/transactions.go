@@ -21,17 +21,21 @@ import (
 	"github.com/playmakerchain/powerplay/powerplay"
 	"github.com/playmakerchain/powerplay/tx"
 	"github.com/playmakerchain/powerplay/txpool"
+	"github.com/playmakerchain/powerplay/txtracker"
 )
 
 type Transactions struct {
-	chain *chain.Chain
-	pool  *txpool.TxPool
+	chain   *chain.Chain
+	pool    *txpool.TxPool
+	tracker *txtracker.Tracker
 }
 
-func New(chain *chain.Chain, pool *txpool.TxPool) *Transactions {
+// New create transactions api. Tx tracking is disabled if tracker is nil.
+func New(chain *chain.Chain, pool *txpool.TxPool, tracker *txtracker.Tracker) *Transactions {
 	return &Transactions{
 		chain,
 		pool,
+		tracker,
 	}
 }
 
@@ -119,16 +123,26 @@ func (t *Transactions) handleSendTransaction(w http.ResponseWriter, req *http.Re
 	if m == nil {
 		return utils.BadRequest(errors.New("body: empty body"))
 	}
+	track, err := t.parseTrack(req.URL.Query().Get("track"))
+	if err != nil {
+		return err
+	}
 	var sendTx = func(tx *tx.Transaction) error {
-		if err := t.pool.Add(tx); err != nil {
-			if txpool.IsBadTx(err) {
-				return utils.BadRequest(err)
-			}
-			if txpool.IsTxRejected(err) {
-				return utils.Forbidden(err)
+		if err := t.pool.AddLocal(tx); err != nil {
+			if rejection := txpool.RejectionOf(err); rejection != nil {
+				status := http.StatusForbidden
+				if txpool.IsBadTx(err) {
+					status = http.StatusBadRequest
+				}
+				return utils.WriteJSONWithStatus(w, status, convertRejection(rejection))
 			}
 			return err
 		}
+		if track {
+			if _, err := t.tracker.TrackTx(tx); err != nil {
+				return utils.Forbidden(errors.WithMessage(err, "tx sent but not tracked"))
+			}
+		}
 		return utils.WriteJSON(w, map[string]string{
 			"id": tx.ID().String(),
 		})
@@ -229,6 +243,49 @@ func (t *Transactions) handleGetTransactionReceiptByID(w http.ResponseWriter, re
 	return utils.WriteJSON(w, receipt)
 }
 
+func (t *Transactions) handleTrackTransaction(w http.ResponseWriter, req *http.Request) error {
+	if t.tracker == nil {
+		return utils.Forbidden(errors.New("tx tracking disabled"))
+	}
+	txID, err := powerplay.ParseBytes32(mux.Vars(req)["id"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "id"))
+	}
+	change, err := t.tracker.Track(txID)
+	if err != nil {
+		return utils.Forbidden(err)
+	}
+	return utils.WriteJSON(w, convertTxStatus(change))
+}
+
+func (t *Transactions) handleGetTransactionStatus(w http.ResponseWriter, req *http.Request) error {
+	if t.tracker == nil {
+		return utils.Forbidden(errors.New("tx tracking disabled"))
+	}
+	txID, err := powerplay.ParseBytes32(mux.Vars(req)["id"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "id"))
+	}
+	change := t.tracker.Get(txID)
+	if change == nil {
+		return utils.WriteJSON(w, nil)
+	}
+	return utils.WriteJSON(w, convertTxStatus(change))
+}
+
+func (t *Transactions) parseTrack(track string) (bool, error) {
+	switch track {
+	case "", "false":
+		return false, nil
+	case "true":
+		if t.tracker == nil {
+			return false, utils.Forbidden(errors.New("track: tx tracking disabled"))
+		}
+		return true, nil
+	}
+	return false, utils.BadRequest(errors.WithMessage(errors.New("should be boolean"), "track"))
+}
+
 func (t *Transactions) parseHead(head string) (powerplay.Bytes32, error) {
 	if head == "" {
 		return t.chain.BestBlock().Header().ID(), nil
@@ -246,4 +303,6 @@ func (t *Transactions) Mount(root *mux.Router, pathPrefix string) {
 	sub.Path("").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(t.handleSendTransaction))
 	sub.Path("/{id}").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(t.handleGetTransactionByID))
 	sub.Path("/{id}/receipt").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(t.handleGetTransactionReceiptByID))
+	sub.Path("/{id}/track").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(t.handleTrackTransaction))
+	sub.Path("/{id}/status").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(t.handleGetTransactionStatus))
 }
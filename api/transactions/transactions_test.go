@@ -31,6 +31,7 @@ import (
 	"github.com/playmakerchain/powerplay/state"
 	"github.com/playmakerchain/powerplay/tx"
 	"github.com/playmakerchain/powerplay/txpool"
+	"github.com/playmakerchain/powerplay/txtracker"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -44,6 +45,8 @@ func TestTransaction(t *testing.T) {
 	getTx(t)
 	getTxReceipt(t)
 	senTx(t)
+	sendRejectedTx(t)
+	trackTx(t)
 }
 
 func getTx(t *testing.T) {
@@ -127,6 +130,50 @@ func senTx(t *testing.T) {
 	assert.Equal(t, tx.ID().String(), txObj["id"], "should be the same transaction id")
 }
 
+func sendRejectedTx(t *testing.T) {
+	tx := new(tx.Builder).
+		ChainTag(c.Tag() + 1).
+		Expiration(10).
+		Gas(21000).
+		Build()
+	sig, err := crypto.Sign(tx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rlpTx, err := rlp.EncodeToBytes(tx.WithSignature(sig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := httpPost(t, ts.URL+"/transactions", transactions.RawTx{Raw: hexutil.Encode(rlpTx)})
+	var rejection transactions.Rejection
+	if err = json.Unmarshal(res, &rejection); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(txpool.RejectChainTagMismatch), rejection.Code)
+	assert.Equal(t, "bad tx: chain tag mismatch", rejection.Message)
+}
+
+func trackTx(t *testing.T) {
+	res := httpGet(t, ts.URL+"/transactions/"+transaction.ID().String()+"/status")
+	assert.Equal(t, "null", string(res), "not tracked yet")
+
+	var status transactions.TxStatus
+	res = httpPost(t, ts.URL+"/transactions/"+transaction.ID().String()+"/track", nil)
+	if err := json.Unmarshal(res, &status); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, transaction.ID(), status.ID)
+	assert.Equal(t, string(txtracker.StatusPacked), status.Status)
+	assert.Equal(t, c.BestBlock().Header().ID(), *status.BlockID)
+
+	res = httpGet(t, ts.URL+"/transactions/"+transaction.ID().String()+"/status")
+	if err := json.Unmarshal(res, &status); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(txtracker.StatusPacked), status.Status)
+}
+
 func httpPost(t *testing.T, url string, obj interface{}) []byte {
 	data, err := json.Marshal(obj)
 	if err != nil {
@@ -209,7 +256,8 @@ func initTransactionServer(t *testing.T) {
 		t.Fatal(err)
 	}
 	router := mux.NewRouter()
-	transactions.New(c, txpool.New(c, stateC, txpool.Options{Limit: 10000, LimitPerAccount: 16, MaxLifetime: 10 * time.Minute})).Mount(router, "/transactions")
+	pool := txpool.New(c, stateC, txpool.Options{Limit: 10000, LimitPerAccount: 16, MaxLifetime: 10 * time.Minute})
+	transactions.New(c, pool, txtracker.New(c, pool)).Mount(router, "/transactions")
 	ts = httptest.NewServer(router)
 
 }
@@ -18,6 +18,8 @@ import (
 	"github.com/playmakerchain/powerplay/block"
 	"github.com/playmakerchain/powerplay/powerplay"
 	"github.com/playmakerchain/powerplay/tx"
+	"github.com/playmakerchain/powerplay/txpool"
+	"github.com/playmakerchain/powerplay/txtracker"
 )
 
 // Clause for json marshal
@@ -68,6 +70,7 @@ type Transaction struct {
 	GasPriceCoef uint8               `json:"gasPriceCoef"`
 	Gas          uint64              `json:"gas"`
 	Origin       powerplay.Address   `json:"origin"`
+	Delegator    *powerplay.Address  `json:"delegator"`
 	Nonce        math.HexOrDecimal64 `json:"nonce"`
 	DependsOn    *powerplay.Bytes32  `json:"dependsOn"`
 	Size         uint32              `json:"size"`
@@ -156,6 +159,10 @@ func convertTransaction(tx *tx.Transaction, header *block.Header, txIndex uint64
 	if err != nil {
 		return nil, err
 	}
+	delegator, err := tx.Delegator()
+	if err != nil {
+		return nil, err
+	}
 	cls := make(Clauses, len(tx.Clauses()))
 	for i, c := range tx.Clauses() {
 		cls[i] = convertClause(c)
@@ -165,6 +172,7 @@ func convertTransaction(tx *tx.Transaction, header *block.Header, txIndex uint64
 		ChainTag:     tx.ChainTag(),
 		ID:           tx.ID(),
 		Origin:       signer,
+		Delegator:    delegator,
 		BlockRef:     hexutil.Encode(br[:]),
 		Expiration:   tx.Expiration(),
 		Nonce:        math.HexOrDecimal64(tx.Nonce()),
@@ -198,13 +206,14 @@ type LogMeta struct {
 
 //Receipt for json marshal
 type Receipt struct {
-	GasUsed  uint64                `json:"gasUsed"`
-	GasPayer powerplay.Address     `json:"gasPayer"`
-	Paid     *math.HexOrDecimal256 `json:"paid"`
-	Reward   *math.HexOrDecimal256 `json:"reward"`
-	Reverted bool                  `json:"reverted"`
-	Meta     LogMeta               `json:"meta"`
-	Outputs  []*Output             `json:"outputs"`
+	GasUsed   uint64                `json:"gasUsed"`
+	GasPayer  powerplay.Address     `json:"gasPayer"`
+	Delegator *powerplay.Address    `json:"delegator"`
+	Paid      *math.HexOrDecimal256 `json:"paid"`
+	Reward    *math.HexOrDecimal256 `json:"reward"`
+	Reverted  bool                  `json:"reverted"`
+	Meta      LogMeta               `json:"meta"`
+	Outputs   []*Output             `json:"outputs"`
 }
 
 // Output output of clause execution.
@@ -236,12 +245,17 @@ func convertReceipt(txReceipt *tx.Receipt, header *block.Header, tx *tx.Transact
 	if err != nil {
 		return nil, err
 	}
+	delegator, err := tx.Delegator()
+	if err != nil {
+		return nil, err
+	}
 	receipt := &Receipt{
-		GasUsed:  txReceipt.GasUsed,
-		GasPayer: txReceipt.GasPayer,
-		Paid:     &paid,
-		Reward:   &reward,
-		Reverted: txReceipt.Reverted,
+		GasUsed:   txReceipt.GasUsed,
+		GasPayer:  txReceipt.GasPayer,
+		Delegator: delegator,
+		Paid:      &paid,
+		Reward:    &reward,
+		Reverted:  txReceipt.Reverted,
 		Meta: LogMeta{
 			header.ID(),
 			header.Number(),
@@ -286,3 +300,37 @@ func convertReceipt(txReceipt *tx.Receipt, header *block.Header, tx *tx.Transact
 	}
 	return receipt, nil
 }
+
+// Rejection describes why a tx is not accepted by the tx pool.
+type Rejection struct {
+	Code      string                `json:"code"`
+	Message   string                `json:"message"`
+	Required  *math.HexOrDecimal256 `json:"required,omitempty"`
+	Available *math.HexOrDecimal256 `json:"available,omitempty"`
+}
+
+func convertRejection(r *txpool.Rejection) *Rejection {
+	return &Rejection{
+		Code:      string(r.Code),
+		Message:   r.Message,
+		Required:  (*math.HexOrDecimal256)(r.Required),
+		Available: (*math.HexOrDecimal256)(r.Available),
+	}
+}
+
+// TxStatus lifecycle status of a tracked tx.
+type TxStatus struct {
+	ID         powerplay.Bytes32  `json:"id"`
+	Status     string             `json:"status"`
+	BlockID    *powerplay.Bytes32 `json:"blockID"`
+	ReplacedBy *powerplay.Bytes32 `json:"replacedBy"`
+}
+
+func convertTxStatus(c *txtracker.Change) *TxStatus {
+	return &TxStatus{
+		ID:         c.TxID,
+		Status:     string(c.Status),
+		BlockID:    c.BlockID,
+		ReplacedBy: c.ReplacedBy,
+	}
+}
@@ -83,11 +83,17 @@ func ParseJSON(r io.Reader, v interface{}) error {
 
 // WriteJSON reponse a object in JSON enconding.
 func WriteJSON(w http.ResponseWriter, obj interface{}) error {
+	return WriteJSONWithStatus(w, http.StatusOK, obj)
+}
+
+// WriteJSONWithStatus reponse a object in JSON enconding with the status code.
+func WriteJSONWithStatus(w http.ResponseWriter, status int, obj interface{}) error {
 	data, err := json.Marshal(obj)
 	if err != nil {
 		return HTTPError(err, 500)
 	}
 	w.Header().Set("Content-Type", JSONContentType)
+	w.WriteHeader(status)
 	w.Write(data)
 	return nil
 }
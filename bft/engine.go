@@ -0,0 +1,292 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package bft
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/builtin"
+	"github.com/playmakerchain/powerplay/chain"
+	"github.com/playmakerchain/powerplay/poa"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/state"
+)
+
+var (
+	errKnownVote    = errors.New("known vote")
+	errStaleVote    = errors.New("stale vote")
+	errNotVoter     = errors.New("signer not a voter")
+	errVoteConflict = errors.New("conflicts with the voter's previous vote")
+)
+
+// IsKnownVote returns whether the error means the vote, or a newer one from the same voter, was already added.
+func IsKnownVote(err error) bool {
+	return err == errKnownVote || err == errStaleVote
+}
+
+// voterSet voters on the state of a block.
+type voterSet struct {
+	blockID powerplay.Bytes32
+	voters  map[powerplay.Address]bool
+	active  map[powerplay.Address]bool
+}
+
+// Engine collects commit votes of authority node masters, and finalizes blocks on chain.
+//
+// Voters are the authority candidates on the state of best block. Each voter's latest vote counts,
+// and a vote on a block supports all its ancestors. A trunk block is finalized once it's supported
+// by more than 2/3 of voters.
+type Engine struct {
+	chain        *chain.Chain
+	stateCreator *state.Creator
+	forkConfig   powerplay.ForkConfig
+
+	votes    map[powerplay.Address]*Vote // voter -> latest vote
+	voterSet *voterSet
+	lock     sync.Mutex
+}
+
+// New create a bft engine.
+func New(chain *chain.Chain, stateCreator *state.Creator) *Engine {
+	return &Engine{
+		chain:        chain,
+		stateCreator: stateCreator,
+		forkConfig:   powerplay.GetForkConfig(chain.GenesisBlock().Header().ID()),
+		votes:        make(map[powerplay.Address]*Vote),
+	}
+}
+
+// AddVote verifies and adds the vote.
+// Votes on unknown blocks are rejected, so they should be retried once the block arrives.
+// Signers are checked ahead, so that only voters' votes are worth retrying.
+// A vote not descending from the voter's previous vote is an equivocation, and rejected,
+// unless the previous vote has expired.
+func (e *Engine) AddVote(vote *Vote) error {
+	signer, err := vote.Signer()
+	if err != nil {
+		return errors.WithMessage(err, "signer")
+	}
+	if vote.Number() <= e.chain.FinalizedBlock().Number() {
+		return errStaleVote
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	vs, err := e.getVoterSet()
+	if err != nil {
+		return err
+	}
+	if !vs.voters[signer] {
+		return errNotVoter
+	}
+	if _, err := e.chain.GetBlockHeader(vote.BlockID); err != nil {
+		return err
+	}
+	if prev, ok := e.votes[signer]; ok {
+		if prev.BlockID == vote.BlockID {
+			return errKnownVote
+		}
+		expired, err := e.expired(prev)
+		if err != nil {
+			return err
+		}
+		if expired {
+			e.votes[signer] = vote
+			return nil
+		}
+		switch {
+		case prev.Number() == vote.Number():
+			return errVoteConflict
+		case prev.Number() > vote.Number():
+			return errStaleVote
+		}
+		ok, err := e.descends(vote.BlockID, prev.BlockID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errVoteConflict
+		}
+	}
+	e.votes[signer] = vote
+	return nil
+}
+
+// ShouldVote returns the block the voter should vote on, or false if no need to vote.
+// Only active voters vote, on best block above and descending from its latest vote.
+// Once the latest vote expires, e.g. orphaned by a reorg, the voter votes on best block again.
+func (e *Engine) ShouldVote(voter powerplay.Address) (powerplay.Bytes32, bool, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	vs, err := e.getVoterSet()
+	if err != nil {
+		return powerplay.Bytes32{}, false, err
+	}
+	if !vs.active[voter] {
+		return powerplay.Bytes32{}, false, nil
+	}
+	best := e.chain.BestBlock().Header()
+	if best.Number() <= e.chain.FinalizedBlock().Number() {
+		return powerplay.Bytes32{}, false, nil
+	}
+	if prev, ok := e.votes[voter]; ok {
+		expired, err := e.expired(prev)
+		if err != nil {
+			return powerplay.Bytes32{}, false, err
+		}
+		if !expired && prev.BlockID == best.ID() {
+			return powerplay.Bytes32{}, false, nil
+		}
+	}
+	return best.ID(), true, nil
+}
+
+// Update tries to finalize blocks with collected votes.
+// The newly finalized block is returned, or nil if finalized block not changed.
+func (e *Engine) Update() (*block.Header, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	vs, err := e.getVoterSet()
+	if err != nil {
+		return nil, err
+	}
+	if len(vs.voters) == 0 {
+		return nil, nil
+	}
+
+	var (
+		finalized = e.chain.FinalizedBlock()
+		best      = e.chain.BestBlock().Header()
+		heights   = make([]uint32, 0, len(e.votes))
+	)
+	for voter, vote := range e.votes {
+		if !vs.voters[voter] {
+			// no longer a voter
+			delete(e.votes, voter)
+			continue
+		}
+		h, err := e.trunkHeight(vote.BlockID, best, finalized.Number())
+		if err != nil {
+			return nil, err
+		}
+		heights = append(heights, h)
+	}
+
+	quorum := len(vs.voters)*2/3 + 1
+	if len(heights) < quorum {
+		return nil, nil
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] > heights[j] })
+	num := heights[quorum-1]
+	if num <= finalized.Number() {
+		return nil, nil
+	}
+
+	id, err := e.chain.GetAncestorBlockID(best.ID(), num)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.chain.SetFinalized(id); err != nil {
+		return nil, err
+	}
+	return e.chain.FinalizedBlock(), nil
+}
+
+// trunkHeight returns height of the highest trunk block supported by the vote on the block.
+func (e *Engine) trunkHeight(blockID powerplay.Bytes32, best *block.Header, floor uint32) (uint32, error) {
+	id := blockID
+	for {
+		num := block.Number(id)
+		if num <= floor {
+			return floor, nil
+		}
+		if num <= best.Number() {
+			trunkID, err := e.chain.GetAncestorBlockID(best.ID(), num)
+			if err != nil {
+				return 0, err
+			}
+			if trunkID == id {
+				return num, nil
+			}
+		}
+		header, err := e.chain.GetBlockHeader(id)
+		if err != nil {
+			return 0, err
+		}
+		id = header.ParentID()
+	}
+}
+
+// expired returns whether the vote no longer restricts the voter's next vote, which is the case
+// once its block is finalized, or off trunk. Otherwise a voter having voted on a block orphaned
+// by a reorg could never vote again.
+func (e *Engine) expired(vote *Vote) (bool, error) {
+	if vote.Number() <= e.chain.FinalizedBlock().Number() {
+		return true, nil
+	}
+	best := e.chain.BestBlock().Header()
+	if vote.BlockID == best.ID() {
+		return false, nil
+	}
+	onTrunk, err := e.descends(best.ID(), vote.BlockID)
+	if err != nil {
+		return false, err
+	}
+	return !onTrunk, nil
+}
+
+// descends returns whether the block is a descendant of the ancestor block.
+func (e *Engine) descends(blockID, ancestorID powerplay.Bytes32) (bool, error) {
+	num := block.Number(ancestorID)
+	if block.Number(blockID) <= num {
+		return false, nil
+	}
+	id, err := e.chain.GetAncestorBlockID(blockID, num)
+	if err != nil {
+		return false, err
+	}
+	return id == ancestorID, nil
+}
+
+// getVoterSet returns voters on best block state, cached until best block changes.
+func (e *Engine) getVoterSet() (*voterSet, error) {
+	best := e.chain.BestBlock().Header()
+	if e.voterSet != nil && e.voterSet.blockID == best.ID() {
+		return e.voterSet, nil
+	}
+
+	st, err := e.stateCreator.NewState(best.StateRoot())
+	if err != nil {
+		return nil, errors.Wrap(err, "state")
+	}
+	var (
+		params      = poa.LoadParams(st, e.forkConfig, best.Number()+1)
+		endorsement = builtin.Params.Native(st).Get(powerplay.KeyProposerEndorsement)
+		candidates  = builtin.Authority.Native(st).Candidates(endorsement, params.MaxBlockProposers)
+		vs          = &voterSet{
+			blockID: best.ID(),
+			voters:  make(map[powerplay.Address]bool, len(candidates)),
+			active:  make(map[powerplay.Address]bool, len(candidates)),
+		}
+	)
+	for _, c := range candidates {
+		vs.voters[c.NodeMaster] = true
+		if c.Active {
+			vs.active[c.NodeMaster] = true
+		}
+	}
+	if err := st.Err(); err != nil {
+		return nil, errors.Wrap(err, "state")
+	}
+	e.voterSet = vs
+	return vs, nil
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package bft_test
+
+import (
+	"crypto/ecdsa"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	. "github.com/playmakerchain/powerplay/bft"
+	"github.com/playmakerchain/powerplay/genesis"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/test/testchain"
+	"github.com/stretchr/testify/assert"
+)
+
+func signVote(blockID powerplay.Bytes32, key *ecdsa.PrivateKey) *Vote {
+	vote := NewVote(blockID)
+	sig, _ := crypto.Sign(vote.SigningHash().Bytes(), key)
+	return vote.WithSignature(sig)
+}
+
+func TestEngine(t *testing.T) {
+	c := testchain.New(t)
+	b0 := c.Genesis
+
+	// the only authority of devnet
+	voter := genesis.DevAccounts()[0]
+	engine := New(c.Chain, c.StateCreator)
+
+	b1 := c.PackBlock(t, b0.Header(), 1)
+	b2 := c.PackBlock(t, b1.Header(), 1)
+
+	id, ok, err := engine.ShouldVote(voter.Address)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, b2.Header().ID(), id)
+
+	_, ok, _ = engine.ShouldVote(genesis.DevAccounts()[1].Address)
+	assert.False(t, ok, "not a voter")
+
+	assert.NotNil(t, engine.AddVote(signVote(b2.Header().ID(), genesis.DevAccounts()[1].PrivateKey)))
+
+	vote := signVote(b2.Header().ID(), voter.PrivateKey)
+	signer, err := vote.Signer()
+	assert.Nil(t, err)
+	assert.Equal(t, voter.Address, signer)
+
+	assert.Nil(t, engine.AddVote(vote))
+	assert.True(t, IsKnownVote(engine.AddVote(vote)))
+	assert.True(t, IsKnownVote(engine.AddVote(signVote(b1.Header().ID(), voter.PrivateKey))))
+
+	finalized, err := engine.Update()
+	assert.Nil(t, err)
+	assert.Equal(t, b2.Header().ID(), finalized.ID())
+	assert.Equal(t, b2.Header().ID(), c.FinalizedBlock().ID())
+
+	finalized, err = engine.Update()
+	assert.Nil(t, err)
+	assert.Nil(t, finalized, "nothing new to finalize")
+
+	_, ok, _ = engine.ShouldVote(voter.Address)
+	assert.False(t, ok, "already voted on best")
+
+	b3 := c.PackBlock(t, b2.Header(), 1)
+	id, ok, _ = engine.ShouldVote(voter.Address)
+	assert.True(t, ok)
+	assert.Equal(t, b3.Header().ID(), id)
+}
+
+func TestVoteConflict(t *testing.T) {
+	c := testchain.New(t)
+	b0 := c.Genesis
+
+	voter := genesis.DevAccounts()[0]
+	engine := New(c.Chain, c.StateCreator)
+
+	b1 := c.PackBlock(t, b0.Header(), 1)
+	b2 := c.PackBlock(t, b1.Header(), 1)
+	c.PackBlock(t, b2.Header(), 1)
+	assert.Nil(t, engine.AddVote(signVote(b1.Header().ID(), voter.PrivateKey)))
+
+	// b1x forks from b0, in the slot after b1's
+	b1x := c.PackBlock(t, b0.Header(), 2)
+	b2x := c.PackBlock(t, b1x.Header(), 1)
+
+	err := engine.AddVote(signVote(b1x.Header().ID(), voter.PrivateKey))
+	assert.NotNil(t, err, "same height as previous vote")
+	assert.False(t, IsKnownVote(err))
+
+	err = engine.AddVote(signVote(b2x.Header().ID(), voter.PrivateKey))
+	assert.NotNil(t, err, "not descending from previous vote")
+	assert.False(t, IsKnownVote(err))
+
+	assert.Nil(t, engine.AddVote(signVote(b2.Header().ID(), voter.PrivateKey)))
+}
+
+func TestVoteAfterReorg(t *testing.T) {
+	c := testchain.New(t)
+	b0 := c.Genesis
+
+	voter := genesis.DevAccounts()[0]
+	engine := New(c.Chain, c.StateCreator)
+
+	// b1x forks from b0, in the slot after b1's
+	b1x := c.PackBlock(t, b0.Header(), 2)
+	id, ok, err := engine.ShouldVote(voter.Address)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, b1x.Header().ID(), id)
+	assert.Nil(t, engine.AddVote(signVote(id, voter.PrivateKey)))
+
+	// b1x orphaned
+	b1 := c.PackBlock(t, b0.Header(), 1)
+	b2 := c.PackBlock(t, b1.Header(), 1)
+	assert.Equal(t, b2.Header().ID(), c.BestBlock().Header().ID())
+
+	id, ok, err = engine.ShouldVote(voter.Address)
+	assert.Nil(t, err)
+	assert.True(t, ok, "vote on orphaned block expired")
+	assert.Equal(t, b2.Header().ID(), id)
+	assert.Nil(t, engine.AddVote(signVote(id, voter.PrivateKey)))
+
+	finalized, err := engine.Update()
+	assert.Nil(t, err)
+	assert.Equal(t, b2.Header().ID(), finalized.ID())
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package bft
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/powerplay"
+)
+
+// Vote is a commit signed by an authority node master on a block.
+// Committing a block implies committing all its ancestors.
+type Vote struct {
+	BlockID   powerplay.Bytes32
+	Signature []byte
+}
+
+// NewVote create an unsigned vote on the block.
+func NewVote(blockID powerplay.Bytes32) *Vote {
+	return &Vote{BlockID: blockID}
+}
+
+// Number returns number of the voted block.
+func (v *Vote) Number() uint32 {
+	return block.Number(v.BlockID)
+}
+
+// SigningHash returns the hash to be signed.
+func (v *Vote) SigningHash() powerplay.Bytes32 {
+	return powerplay.Blake2b([]byte("commit"), v.BlockID[:])
+}
+
+// Hash returns hash of the signed vote, to identify it.
+func (v *Vote) Hash() powerplay.Bytes32 {
+	return powerplay.Blake2b(v.BlockID[:], v.Signature)
+}
+
+// WithSignature create a new vote with signature set.
+func (v *Vote) WithSignature(sig []byte) *Vote {
+	return &Vote{
+		BlockID:   v.BlockID,
+		Signature: append([]byte(nil), sig...),
+	}
+}
+
+// Signer extract signer of the vote from signature.
+func (v *Vote) Signer() (powerplay.Address, error) {
+	pub, err := crypto.SigToPub(v.SigningHash().Bytes(), v.Signature)
+	if err != nil {
+		return powerplay.Address{}, err
+	}
+	return powerplay.Address(crypto.PubkeyToAddress(*pub)), nil
+}
+
+func (v *Vote) String() string {
+	return fmt.Sprintf("Vote(%v)", v.BlockID)
+}
@@ -52,8 +52,18 @@ func (e *Energy) getTotalAddSub() (total totalAddSub) {
 	})
 	return
 }
-func (e *Energy) setTotalAddSub(total totalAddSub) {
-	e.state.EncodeStorage(e.addr, totalAddSubKey, func() ([]byte, error) {
+
+// modifyTotalAddSub updates total add/sub by fn, without reading it out, so that txs
+// adding or subbing energy don't conflict when executed speculatively.
+func (e *Energy) modifyTotalAddSub(fn func(total *totalAddSub)) {
+	e.state.ModifyRawStorage(e.addr, totalAddSubKey, func(raw rlp.RawValue) (rlp.RawValue, error) {
+		total := totalAddSub{&big.Int{}, &big.Int{}}
+		if len(raw) > 0 {
+			if err := rlp.DecodeBytes(raw, &total); err != nil {
+				return nil, err
+			}
+		}
+		fn(&total)
 		return rlp.EncodeToBytes(&total)
 	})
 }
@@ -102,13 +112,10 @@ func (e *Energy) Add(addr powerplay.Address, amount *big.Int) {
 	if amount.Sign() == 0 {
 		return
 	}
-	eng := e.state.GetEnergy(addr, e.blockTime)
-
-	total := e.getTotalAddSub()
-	total.TotalAdd = new(big.Int).Add(total.TotalAdd, amount)
-	e.setTotalAddSub(total)
-
-	e.state.SetEnergy(addr, new(big.Int).Add(eng, amount), e.blockTime)
+	e.modifyTotalAddSub(func(total *totalAddSub) {
+		total.TotalAdd = new(big.Int).Add(total.TotalAdd, amount)
+	})
+	e.state.AddEnergy(addr, amount, e.blockTime)
 }
 
 // Sub sub amount of energy from given address.
@@ -121,10 +128,9 @@ func (e *Energy) Sub(addr powerplay.Address, amount *big.Int) bool {
 	if eng.Cmp(amount) < 0 {
 		return false
 	}
-	total := e.getTotalAddSub()
-	total.TotalSub = new(big.Int).Add(total.TotalSub, amount)
-	e.setTotalAddSub(total)
-
+	e.modifyTotalAddSub(func(total *totalAddSub) {
+		total.TotalSub = new(big.Int).Add(total.TotalSub, amount)
+	})
 	e.state.SetEnergy(addr, new(big.Int).Sub(eng, amount), e.blockTime)
 	return true
 }
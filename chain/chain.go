@@ -26,6 +26,7 @@ const (
 
 var errNotFound = errors.New("not found")
 var errBlockExist = errors.New("block already exists")
+var errFinalizedConflict = errors.New("conflicts with finalized block")
 
 // Chain describes a persistent block chain.
 // It's thread-safe.
@@ -34,6 +35,7 @@ type Chain struct {
 	ancestorTrie *ancestorTrie
 	genesisBlock *block.Block
 	bestBlock    *block.Block
+	finalized    *block.Header
 	tag          byte
 	caches       caches
 	rw           sync.RWMutex
@@ -103,6 +105,21 @@ func New(kv kv.GetPutter, genesisBlock *block.Block) (*Chain, error) {
 		}
 	}
 
+	finalized := genesisBlock.Header()
+	if finalizedID, err := loadFinalizedBlockID(kv); err != nil {
+		if !kv.IsNotFound(err) {
+			return nil, err
+		}
+	} else {
+		raw, err := loadBlockRaw(kv, finalizedID)
+		if err != nil {
+			return nil, err
+		}
+		if finalized, err = (&rawBlock{raw: raw}).Header(); err != nil {
+			return nil, err
+		}
+	}
+
 	rawBlocksCache := newCache(blockCacheLimit, func(key interface{}) (interface{}, error) {
 		raw, err := loadBlockRaw(kv, key.(powerplay.Bytes32))
 		if err != nil {
@@ -120,6 +137,7 @@ func New(kv kv.GetPutter, genesisBlock *block.Block) (*Chain, error) {
 		ancestorTrie: ancestorTrie,
 		genesisBlock: genesisBlock,
 		bestBlock:    bestBlock,
+		finalized:    finalized,
 		tag:          genesisBlock.Header().ID()[31],
 		caches: caches{
 			rawBlocks: rawBlocksCache,
@@ -145,9 +163,49 @@ func (c *Chain) BestBlock() *block.Block {
 	return c.bestBlock
 }
 
+// FinalizedBlock returns header of the finalized block, which never gets reorged out.
+// It's genesis block if no block finalized yet.
+func (c *Chain) FinalizedBlock() *block.Header {
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+	return c.finalized
+}
+
+// SetFinalized marks the trunk block as finalized.
+// Blocks can only be finalized forward, and blocks conflict with it will be refused afterwards.
+func (c *Chain) SetFinalized(id powerplay.Bytes32) error {
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	num := block.Number(id)
+	if num <= c.finalized.Number() {
+		return errors.New("not above current finalized block")
+	}
+	if num > c.bestBlock.Header().Number() {
+		return errors.New("not on trunk")
+	}
+	trunkID, err := c.ancestorTrie.GetAncestor(c.bestBlock.Header().ID(), num)
+	if err != nil {
+		return err
+	}
+	if trunkID != id {
+		return errors.New("not on trunk")
+	}
+	header, err := c.getBlockHeader(id)
+	if err != nil {
+		return err
+	}
+	if err := saveFinalizedBlockID(c.kv, id); err != nil {
+		return err
+	}
+	c.finalized = header
+	return nil
+}
+
 // AddBlock add a new block into block chain.
 // Once reorg happened (len(Trunk) > 0 && len(Branch) >0), Fork.Branch will be the chain transitted from trunk to branch.
 // Reorg happens when isTrunk is true.
+// Blocks not descended from the finalized block are refused.
 func (c *Chain) AddBlock(newBlock *block.Block, receipts tx.Receipts) (*Fork, error) {
 	c.rw.Lock()
 	defer c.rw.Unlock()
@@ -171,6 +229,19 @@ func (c *Chain) AddBlock(newBlock *block.Block, receipts tx.Receipts) (*Fork, er
 		return nil, err
 	}
 
+	if finalizedNum := c.finalized.Number(); finalizedNum > 0 {
+		if parent.Number() < finalizedNum {
+			return nil, errFinalizedConflict
+		}
+		ancestorID, err := c.ancestorTrie.GetAncestor(parent.ID(), finalizedNum)
+		if err != nil {
+			return nil, err
+		}
+		if ancestorID != c.finalized.ID() {
+			return nil, errFinalizedConflict
+		}
+	}
+
 	raw, err := rlp.EncodeToBytes(newBlock)
 	if err != nil {
 		return nil, err
@@ -234,6 +305,62 @@ func (c *Chain) AddBlock(newBlock *block.Block, receipts tx.Receipts) (*Fork, er
 	return fork, nil
 }
 
+// Rewind resets the best block to the trunk block with given number.
+// Trunk blocks above are removed along with their receipts, number indices and tx metas,
+// so that they can be re-imported later.
+// Removed blocks are returned, from the old best block downwards.
+func (c *Chain) Rewind(num uint32) ([]*block.Block, error) {
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	bestHeader := c.bestBlock.Header()
+	if num >= bestHeader.Number() {
+		return nil, errors.New("rewind target should be below best block")
+	}
+	if num < c.finalized.Number() {
+		return nil, errors.New("rewind target should not be below finalized block")
+	}
+	newBestID, err := c.ancestorTrie.GetAncestor(bestHeader.ID(), num)
+	if err != nil {
+		return nil, err
+	}
+	newBest, err := c.getBlock(newBestID)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := c.kv.NewBatch()
+	var removed []*block.Block
+	for id := bestHeader.ID(); id != newBestID; {
+		b, err := c.getBlock(id)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := deleteBlock(c.kv, batch, id); err != nil {
+			return nil, err
+		}
+		removed = append(removed, b)
+		id = b.Header().ParentID()
+	}
+	if err := saveBestBlockID(batch, newBestID); err != nil {
+		return nil, err
+	}
+	if err := batch.Write(); err != nil {
+		return nil, err
+	}
+
+	for _, b := range removed {
+		id := b.Header().ID()
+		c.caches.rawBlocks.Remove(id)
+		c.caches.receipts.Remove(id)
+		c.ancestorTrie.rootsCache.Remove(id)
+	}
+	c.bestBlock = newBest
+
+	c.tick.Broadcast()
+	return removed, nil
+}
+
 // GetBlockHeader get block header by block id.
 func (c *Chain) GetBlockHeader(id powerplay.Bytes32) (*block.Header, error) {
 	c.rw.RLock()
@@ -531,6 +658,11 @@ func (c *Chain) IsNotFound(err error) bool {
 	return err == errNotFound || c.kv.IsNotFound(err)
 }
 
+// IsFinalizedConflict returns if the error means block conflicts with the finalized block.
+func (c *Chain) IsFinalizedConflict(err error) bool {
+	return err == errFinalizedConflict
+}
+
 // IsBlockExist returns if the error means block was already in the chain.
 func (c *Chain) IsBlockExist(err error) bool {
 	return err == errBlockExist
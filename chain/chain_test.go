@@ -75,3 +75,61 @@ func TestAdd(t *testing.T) {
 		}
 	}
 }
+
+func TestRewind(t *testing.T) {
+	ch := initChain()
+	b0 := ch.GenesisBlock()
+	b1 := newBlock(b0, 1)
+	b2 := newBlock(b1, 1)
+	b3 := newBlock(b2, 1)
+	for _, b := range []*block.Block{b1, b2, b3} {
+		_, err := ch.AddBlock(b, nil)
+		assert.Nil(t, err)
+	}
+
+	_, err := ch.Rewind(3)
+	assert.NotNil(t, err)
+
+	removed, err := ch.Rewind(1)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(removed))
+	assert.Equal(t, b3.Header().ID(), removed[0].Header().ID())
+	assert.Equal(t, b2.Header().ID(), removed[1].Header().ID())
+	assert.Equal(t, b1.Header().ID(), ch.BestBlock().Header().ID())
+
+	_, err = ch.GetBlockHeader(b2.Header().ID())
+	assert.True(t, ch.IsNotFound(err))
+
+	// able to re-import
+	_, err = ch.AddBlock(b2, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, b2.Header().ID(), ch.BestBlock().Header().ID())
+}
+
+func TestFinalized(t *testing.T) {
+	ch := initChain()
+	b0 := ch.GenesisBlock()
+	b1 := newBlock(b0, 1)
+	b2 := newBlock(b1, 1)
+	b2x := newBlock(b1, 2)
+	b3 := newBlock(b2, 1)
+	for _, b := range []*block.Block{b1, b2, b3} {
+		_, err := ch.AddBlock(b, nil)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, b0.Header().ID(), ch.FinalizedBlock().ID())
+
+	assert.NotNil(t, ch.SetFinalized(b0.Header().ID()), "not above current")
+	assert.Nil(t, ch.SetFinalized(b2.Header().ID()))
+	assert.Equal(t, b2.Header().ID(), ch.FinalizedBlock().ID())
+	assert.NotNil(t, ch.SetFinalized(b1.Header().ID()), "finalized backward")
+
+	_, err := ch.AddBlock(b2x, nil)
+	assert.True(t, ch.IsFinalizedConflict(err))
+
+	_, err = ch.Rewind(1)
+	assert.NotNil(t, err, "rewind below finalized")
+
+	_, err = ch.AddBlock(newBlock(b3, 1), nil)
+	assert.Nil(t, err)
+}
@@ -7,6 +7,8 @@
 package chain
 
 import (
+	"bytes"
+
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/playmakerchain/powerplay/block"
 	"github.com/playmakerchain/powerplay/kv"
@@ -16,12 +18,40 @@ import (
 
 var (
 	bestBlockKey        = []byte("best")
+	finalizedBlockKey   = []byte("finalized")
 	blockPrefix         = []byte("b") // (prefix, block id) -> block
 	txMetaPrefix        = []byte("t") // (prefix, tx id) -> tx location
 	blockReceiptsPrefix = []byte("r") // (prefix, block id) -> receipts
 	indexTrieRootPrefix = []byte("i") // (prefix, block id) -> trie root
 )
 
+// KeyCategory returns name of the category the key in main db belongs to, for statistics purpose.
+// Keys without known prefix are hashes, which map to trie nodes or contract codes.
+func KeyCategory(key []byte) string {
+	if bytes.Equal(key, bestBlockKey) {
+		return "best block"
+	}
+	if bytes.Equal(key, finalizedBlockKey) {
+		return "finalized block"
+	}
+	if len(key) == 1+len(powerplay.Bytes32{}) {
+		switch key[0] {
+		case blockPrefix[0]:
+			return "blocks"
+		case txMetaPrefix[0]:
+			return "tx metas"
+		case blockReceiptsPrefix[0]:
+			return "receipts"
+		case indexTrieRootPrefix[0]:
+			return "index trie roots"
+		}
+	}
+	if len(key) == len(powerplay.Bytes32{}) {
+		return "trie nodes & codes"
+	}
+	return "others"
+}
+
 // TxMeta contains information about a tx is settled.
 type TxMeta struct {
 	BlockID powerplay.Bytes32
@@ -62,6 +92,20 @@ func saveBestBlockID(w kv.Putter, id powerplay.Bytes32) error {
 	return w.Put(bestBlockKey, id[:])
 }
 
+// loadFinalizedBlockID returns the finalized block ID.
+func loadFinalizedBlockID(r kv.Getter) (powerplay.Bytes32, error) {
+	data, err := r.Get(finalizedBlockKey)
+	if err != nil {
+		return powerplay.Bytes32{}, err
+	}
+	return powerplay.BytesToBytes32(data), nil
+}
+
+// saveFinalizedBlockID save the finalized block ID.
+func saveFinalizedBlockID(w kv.Putter, id powerplay.Bytes32) error {
+	return w.Put(finalizedBlockKey, id[:])
+}
+
 // loadBlockRaw load rlp encoded block raw data.
 func loadBlockRaw(r kv.Getter, id powerplay.Bytes32) (block.Raw, error) {
 	return r.Get(append(blockPrefix, id[:]...))
@@ -113,3 +157,81 @@ func loadBlockReceipts(r kv.Getter, blockID powerplay.Bytes32) (tx.Receipts, err
 	}
 	return receipts, nil
 }
+
+// deleteTxMeta removes the location in given block from tx metas.
+func deleteTxMeta(r kv.Getter, w kv.Putter, txID powerplay.Bytes32, blockID powerplay.Bytes32) error {
+	meta, err := loadTxMeta(r, txID)
+	if err != nil {
+		if r.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	remained := meta[:0]
+	for _, m := range meta {
+		if m.BlockID != blockID {
+			remained = append(remained, m)
+		}
+	}
+	if len(remained) == 0 {
+		return w.Delete(append(txMetaPrefix, txID[:]...))
+	}
+	return saveTxMeta(w, txID, remained)
+}
+
+// purgeTxMetas removes locations in given blocks from tx metas, by scanning all tx metas.
+// It's for blocks whose txs are unknown, e.g. corrupted blocks.
+func purgeTxMetas(r kv.Getter, w kv.Putter, blockIDs map[powerplay.Bytes32]bool) error {
+	it := r.NewIterator(*kv.NewRangeWithBytesPrefix(txMetaPrefix))
+	defer it.Release()
+	for it.Next() {
+		if len(it.Key()) != len(txMetaPrefix)+len(powerplay.Bytes32{}) {
+			continue
+		}
+		var meta []TxMeta
+		if err := rlp.DecodeBytes(it.Value(), &meta); err != nil {
+			// not a tx meta
+			continue
+		}
+		remained := meta[:0]
+		for _, m := range meta {
+			if !blockIDs[m.BlockID] {
+				remained = append(remained, m)
+			}
+		}
+		if len(remained) == len(meta) {
+			continue
+		}
+		key := append([]byte(nil), it.Key()...)
+		if len(remained) == 0 {
+			if err := w.Delete(key); err != nil {
+				return err
+			}
+		} else if err := saveRLP(w, key, remained); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// deleteBlock removes the block with its receipts, number index trie root and tx metas.
+// Tx metas are left untouched if the block is not decodable, which is reported by txMetasDeleted.
+func deleteBlock(r kv.Getter, w kv.Putter, id powerplay.Bytes32) (txMetasDeleted bool, err error) {
+	if raw, err := loadBlockRaw(r, id); err == nil {
+		if body, err := raw.DecodeBody(); err == nil {
+			for _, tx := range body.Txs {
+				if err := deleteTxMeta(r, w, tx.ID(), id); err != nil {
+					return false, err
+				}
+			}
+			txMetasDeleted = true
+		}
+	}
+	if err := w.Delete(append(blockPrefix, id[:]...)); err != nil {
+		return false, err
+	}
+	if err := w.Delete(append(blockReceiptsPrefix, id[:]...)); err != nil {
+		return false, err
+	}
+	return txMetasDeleted, w.Delete(append(indexTrieRootPrefix, id[:]...))
+}
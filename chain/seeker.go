@@ -31,6 +31,11 @@ func (s *Seeker) setError(err error) {
 	}
 }
 
+// Copy returns a copy of the seeker, without error occurred. Copies can be used concurrently.
+func (s *Seeker) Copy() *Seeker {
+	return newSeeker(s.chain, s.headBlockID)
+}
+
 // Err returns error occurred.
 func (s *Seeker) Err() error {
 	return s.err
@@ -0,0 +1,164 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package chain
+
+import (
+	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/kv"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/tx"
+)
+
+// Verifier checks consistency of chain data persisted in kv store.
+// It works on kv directly, so it's usable even if Chain fails to be loaded.
+type Verifier struct {
+	kv           kv.GetPutter
+	ancestorTrie *ancestorTrie
+}
+
+// NewVerifier create a verifier instance.
+func NewVerifier(kv kv.GetPutter) *Verifier {
+	return &Verifier{
+		kv,
+		newAncestorTrie(kv),
+	}
+}
+
+// BestBlockID returns the persisted best block ID.
+func (v *Verifier) BestBlockID() (powerplay.Bytes32, error) {
+	return loadBestBlockID(v.kv)
+}
+
+// GetAncestor get ancestor block ID of descendant via block number index.
+func (v *Verifier) GetAncestor(descendantID powerplay.Bytes32, ancestorNum uint32) (powerplay.Bytes32, error) {
+	return v.ancestorTrie.GetAncestor(descendantID, ancestorNum)
+}
+
+// GetBlockReceipts get all tx receipts in the block for given block id.
+func (v *Verifier) GetBlockReceipts(id powerplay.Bytes32) (tx.Receipts, error) {
+	return loadBlockReceipts(v.kv, id)
+}
+
+// VerifyBlock checks the block, its receipts, number index and tx metas.
+// The header is returned once the block is decodable, even if other checks failed.
+func (v *Verifier) VerifyBlock(id powerplay.Bytes32) (*block.Header, error) {
+	raw, err := loadBlockRaw(v.kv, id)
+	if err != nil {
+		return nil, errors.WithMessage(err, "load block")
+	}
+	blk, err := (&rawBlock{raw: raw}).Block()
+	if err != nil {
+		return nil, errors.WithMessage(err, "decode block")
+	}
+	header := blk.Header()
+	if header.ID() != id {
+		return nil, errors.New("block id mismatch")
+	}
+	txs := blk.Transactions()
+	if txs.RootHash() != header.TxsRoot() {
+		return header, errors.New("txs root mismatch")
+	}
+
+	if _, err := loadBlockNumberIndexTrieRoot(v.kv, id); err != nil {
+		return header, errors.WithMessage(err, "load index root")
+	}
+	if header.Number() == 0 {
+		// genesis has no receipts
+		return header, nil
+	}
+
+	parentID, err := v.ancestorTrie.GetAncestor(id, header.Number()-1)
+	if err != nil {
+		return header, errors.WithMessage(err, "load ancestor")
+	}
+	if parentID != header.ParentID() {
+		return header, errors.New("number index mismatch")
+	}
+
+	receipts, err := loadBlockReceipts(v.kv, id)
+	if err != nil {
+		return header, errors.WithMessage(err, "load receipts")
+	}
+	if len(receipts) != len(txs) {
+		return header, errors.New("receipts count mismatch")
+	}
+	if receipts.RootHash() != header.ReceiptsRoot() {
+		return header, errors.New("receipts root mismatch")
+	}
+
+	for i, tx := range txs {
+		metas, err := loadTxMeta(v.kv, tx.ID())
+		if err != nil {
+			return header, errors.WithMessage(err, "load tx meta")
+		}
+		found := false
+		for _, m := range metas {
+			if m.BlockID == id && m.Index == uint64(i) {
+				if m.Reverted != receipts[i].Reverted {
+					return header, errors.New("tx meta reverted flag mismatch")
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return header, errors.New("tx meta missing")
+		}
+	}
+	return header, nil
+}
+
+// ResetBestBlock sets the best block pointer to the given block.
+// Data of abandoned blocks are removed, to let them be re-imported later.
+func (v *Verifier) ResetBestBlock(id powerplay.Bytes32, abandoned []powerplay.Bytes32) error {
+	if _, err := loadBlockRaw(v.kv, id); err != nil {
+		return errors.WithMessage(err, "load block")
+	}
+	batch := v.kv.NewBatch()
+	corrupted := false
+	for _, abandonedID := range abandoned {
+		txMetasDeleted, err := deleteBlock(v.kv, batch, abandonedID)
+		if err != nil {
+			return err
+		}
+		corrupted = corrupted || !txMetasDeleted
+	}
+	if corrupted {
+		// txs of corrupted blocks are unknown, so sweep all tx metas.
+		// it supersedes tx metas deleted above, since the batch is not visible to reads.
+		set := make(map[powerplay.Bytes32]bool, len(abandoned))
+		for _, id := range abandoned {
+			set[id] = true
+		}
+		if err := purgeTxMetas(v.kv, batch, set); err != nil {
+			return err
+		}
+	}
+	if err := saveBestBlockID(batch, id); err != nil {
+		return err
+	}
+	// the finalized block can't be above best block, or chain would fail to load or refuse to re-sync
+	if finalizedID, err := loadFinalizedBlockID(v.kv); err == nil && !isFinalizedKept(finalizedID, id, abandoned) {
+		if err := saveFinalizedBlockID(batch, id); err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+// isFinalizedKept returns whether the finalized block is still at or below the best block after reset.
+func isFinalizedKept(finalizedID, bestID powerplay.Bytes32, abandoned []powerplay.Bytes32) bool {
+	if block.Number(finalizedID) > block.Number(bestID) {
+		return false
+	}
+	for _, id := range abandoned {
+		if id == finalizedID {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package chain_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/chain"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/test/testchain"
+	"github.com/playmakerchain/powerplay/tx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifier(t *testing.T) {
+	tc := testchain.New(t)
+	kv, ch, b0 := tc.KV, tc.Chain, tc.Genesis
+
+	var ids []powerplay.Bytes32
+	parent := b0
+	for i := 0; i < 3; i++ {
+		b := new(block.Builder).
+			ParentID(parent.Header().ID()).
+			TotalScore(parent.Header().TotalScore() + 1).
+			ReceiptsRoot(tx.Receipts(nil).RootHash()).
+			Build()
+		sig, _ := crypto.Sign(b.Header().SigningHash().Bytes(), privateKey)
+		b = b.WithSignature(sig)
+		_, err := ch.AddBlock(b, nil)
+		assert.Nil(t, err)
+		ids = append(ids, b.Header().ID())
+		parent = b
+	}
+
+	v := chain.NewVerifier(kv)
+	bestID, err := v.BestBlockID()
+	assert.Nil(t, err)
+	assert.Equal(t, ids[2], bestID)
+
+	for _, id := range ids {
+		header, err := v.VerifyBlock(id)
+		assert.Nil(t, err)
+		assert.Equal(t, id, header.ID())
+	}
+
+	// corrupt receipts
+	assert.Nil(t, kv.Delete(append([]byte("r"), ids[1][:]...)))
+	header, err := v.VerifyBlock(ids[1])
+	assert.NotNil(t, err)
+	assert.Equal(t, ids[1], header.ID())
+
+	assert.Nil(t, v.ResetBestBlock(ids[0], ids[1:]))
+	bestID, err = v.BestBlockID()
+	assert.Nil(t, err)
+	assert.Equal(t, ids[0], bestID)
+
+	_, err = v.VerifyBlock(ids[2])
+	assert.NotNil(t, err)
+}
+
+func TestResetBestBlockCorrupted(t *testing.T) {
+	tc := testchain.New(t)
+	kv, ch, b0 := tc.KV, tc.Chain, tc.Genesis
+
+	trx := new(tx.Builder).Nonce(1).Build()
+	receipts := tx.Receipts{&tx.Receipt{}}
+	b1 := new(block.Builder).
+		ParentID(b0.Header().ID()).
+		TotalScore(b0.Header().TotalScore() + 1).
+		ReceiptsRoot(receipts.RootHash()).
+		Transaction(trx).
+		Build()
+	sig, _ := crypto.Sign(b1.Header().SigningHash().Bytes(), privateKey)
+	b1 = b1.WithSignature(sig)
+	_, err := ch.AddBlock(b1, receipts)
+	assert.Nil(t, err)
+
+	txMetaKey := append([]byte("t"), trx.ID().Bytes()...)
+	has, _ := kv.Has(txMetaKey)
+	assert.True(t, has)
+
+	// corrupt the block, so that its txs are unknown
+	id := b1.Header().ID()
+	assert.Nil(t, kv.Put(append([]byte("b"), id[:]...), []byte("corrupted")))
+
+	v := chain.NewVerifier(kv)
+	assert.Nil(t, v.ResetBestBlock(b0.Header().ID(), []powerplay.Bytes32{id}))
+	has, _ = kv.Has(txMetaKey)
+	assert.False(t, has, "tx meta of corrupted block purged")
+}
+
+func TestResetBestBlockFinalized(t *testing.T) {
+	tc := testchain.New(t)
+
+	b1 := tc.PackBlock(t, tc.Genesis.Header(), 1)
+	b2 := tc.PackBlock(t, b1.Header(), 1)
+	b3 := tc.PackBlock(t, b2.Header(), 1)
+	assert.Nil(t, tc.SetFinalized(b2.Header().ID()))
+
+	v := chain.NewVerifier(tc.KV)
+	assert.Nil(t, v.ResetBestBlock(b1.Header().ID(), []powerplay.Bytes32{b3.Header().ID(), b2.Header().ID()}))
+
+	// reopen
+	ch, err := chain.New(tc.KV, tc.Genesis)
+	assert.Nil(t, err)
+	assert.Equal(t, b1.Header().ID(), ch.BestBlock().Header().ID())
+	assert.Equal(t, b1.Header().ID(), ch.FinalizedBlock().ID(), "finalized block clamped to best block")
+
+	// able to re-sync abandoned blocks
+	_, err = ch.AddBlock(b2, nil)
+	assert.Nil(t, err)
+	_, err = ch.AddBlock(b3, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, b3.Header().ID(), ch.BestBlock().Header().ID())
+}
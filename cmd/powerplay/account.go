@@ -0,0 +1,134 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	isatty "github.com/mattn/go-isatty"
+	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/keystore"
+	"github.com/playmakerchain/powerplay/powerplay"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+func openKeystore(ctx *cli.Context) *keystore.Keystore {
+	return keystore.New(filepath.Join(makeConfigDir(ctx), "keystore"))
+}
+
+// readPassword reads password from password file if specified, or from tty.
+func readPassword(ctx *cli.Context, confirm bool) (string, error) {
+	if path := ctx.String(passwordFileFlag.Name); path != "" {
+		return keystore.ReadPasswordFile(path)
+	}
+	password, err := readPasswordFromNewTTY("Enter passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if !confirm {
+		return password, nil
+	}
+	if password == "" {
+		return "", errors.New("non-empty passphrase required")
+	}
+	again, err := readPasswordFromNewTTY("Confirm passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if password != again {
+		return "", errors.New("passphrase confirmation mismatch")
+	}
+	return password, nil
+}
+
+// unlockAccount unlocks the keystore account specified by account flag.
+// Nil returned if the flag is not set.
+func unlockAccount(ctx *cli.Context) (*ecdsa.PrivateKey, error) {
+	value := ctx.String(accountFlag.Name)
+	if value == "" {
+		return nil, nil
+	}
+	addr, err := powerplay.ParseAddress(value)
+	if err != nil {
+		return nil, errors.WithMessage(err, "account")
+	}
+	password, err := readPassword(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	return openKeystore(ctx).Unlock(addr, password)
+}
+
+// loadSigningKey unlocks the keystore account if specified, or loads the master key.
+func loadSigningKey(ctx *cli.Context) (*ecdsa.PrivateKey, error) {
+	key, err := unlockAccount(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "unlock account")
+	}
+	if key == nil {
+		if key, err = loadOrGeneratePrivateKey(masterKeyPath(ctx)); err != nil {
+			return nil, errors.WithMessage(err, "load or generate master key")
+		}
+	}
+	return key, nil
+}
+
+func accountListAction(ctx *cli.Context) error {
+	accounts, err := openKeystore(ctx).Accounts()
+	if err != nil {
+		return err
+	}
+	for i, acc := range accounts {
+		fmt.Printf("#%v %v %v\n", i, acc.Address, acc.Path)
+	}
+	return nil
+}
+
+func accountNewAction(ctx *cli.Context) error {
+	password, err := readPassword(ctx, true)
+	if err != nil {
+		return err
+	}
+	acc, err := openKeystore(ctx).Create(password)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Account created:", acc.Address)
+	return nil
+}
+
+func accountImportAction(ctx *cli.Context) error {
+	if isatty.IsTerminal(os.Stdin.Fd()) {
+		fmt.Println("Input mnemonic words:")
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return errors.WithMessage(err, "read mnemonic")
+	}
+	path := fmt.Sprintf("%v/%v", strings.TrimRight(ctx.String(derivationPathFlag.Name), "/"), ctx.Uint64(accountIndexFlag.Name))
+	key, err := keystore.DeriveFromMnemonic(line, "", path)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Derived account:", powerplay.Address(crypto.PubkeyToAddress(key.PublicKey)), "at", path)
+
+	password, err := readPassword(ctx, true)
+	if err != nil {
+		return err
+	}
+	acc, err := openKeystore(ctx).Import(key, password)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Account imported:", acc.Address)
+	return nil
+}
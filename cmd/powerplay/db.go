@@ -0,0 +1,355 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/chain"
+	"github.com/playmakerchain/powerplay/kv"
+	"github.com/playmakerchain/powerplay/logdb"
+	"github.com/playmakerchain/powerplay/lvldb"
+	"github.com/playmakerchain/powerplay/metric"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/state"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+// trunkCheck is the result of walking through trunk blocks.
+type trunkCheck struct {
+	bestID   powerplay.Bytes32
+	headID   powerplay.Bytes32 // the highest block consistent with all its ancestors
+	problems int
+}
+
+func dbVerifyAction(ctx *cli.Context) error {
+	initLogger(ctx)
+	gene := selectGenesis(ctx)
+	instanceDir := makeInstanceDir(ctx, gene)
+
+	mainDB := openMainDB(ctx, instanceDir)
+	defer mainDB.Close()
+
+	logDB := openLogDB(ctx, instanceDir)
+	defer logDB.Close()
+
+	check, err := checkTrunk(mainDB, logDB, gene.ID(), uint32(ctx.Int(stateDepthFlag.Name)))
+	if err != nil {
+		return err
+	}
+	if check.problems > 0 {
+		return fmt.Errorf("%v inconsistent block(s) found, last consistent block #%v %v",
+			check.problems, block.Number(check.headID), check.headID)
+	}
+	fmt.Println("database is consistent, best block", check.bestID)
+	return nil
+}
+
+func dbRepairAction(ctx *cli.Context) error {
+	initLogger(ctx)
+	gene := selectGenesis(ctx)
+	instanceDir := makeInstanceDir(ctx, gene)
+
+	mainDB := openMainDB(ctx, instanceDir)
+	defer mainDB.Close()
+
+	logDB := openLogDB(ctx, instanceDir)
+	defer logDB.Close()
+
+	check, err := checkTrunk(mainDB, logDB, gene.ID(), uint32(ctx.Int(stateDepthFlag.Name)))
+	if err != nil {
+		return err
+	}
+	if check.problems == 0 {
+		fmt.Println("database is consistent, nothing to repair")
+		return nil
+	}
+
+	verifier := chain.NewVerifier(mainDB)
+	stateVerifier := state.NewVerifier(mainDB)
+
+	// the new head must have a resolvable state
+	headID := check.headID
+	for {
+		header, err := verifier.VerifyBlock(headID)
+		if err != nil {
+			return errors.WithMessage(err, "verify new head")
+		}
+		if err := stateVerifier.Verify(header.StateRoot()); err == nil {
+			break
+		}
+		if header.Number() == 0 {
+			return errors.New("genesis state is not resolvable")
+		}
+		headID = header.ParentID()
+	}
+
+	var abandoned []powerplay.Bytes32
+	for id := check.bestID; block.Number(id) > block.Number(headID); {
+		abandoned = append(abandoned, id)
+		header, _ := verifier.VerifyBlock(id)
+		if id, err = trunkParentID(verifier, check.bestID, id, header); err != nil {
+			return err
+		}
+	}
+
+	if err := verifier.ResetBestBlock(headID, abandoned); err != nil {
+		return errors.WithMessage(err, "reset best block")
+	}
+	if err := logDB.Truncate(block.Number(headID)); err != nil {
+		return errors.WithMessage(err, "truncate logs")
+	}
+	fmt.Printf("best block rewound to #%v %v, %v block(s) abandoned\n", block.Number(headID), headID, len(abandoned))
+	return nil
+}
+
+func rewindAction(ctx *cli.Context) error {
+	to := ctx.Int(rewindToFlag.Name)
+	if to < 0 {
+		return fmt.Errorf("missing or invalid flag -%s", rewindToFlag.Name)
+	}
+
+	initLogger(ctx)
+	gene := selectGenesis(ctx)
+	instanceDir := makeInstanceDir(ctx, gene)
+
+	mainDB := openMainDB(ctx, instanceDir)
+	defer mainDB.Close()
+
+	logDB := openLogDB(ctx, instanceDir)
+	defer logDB.Close()
+
+	chain := initChain(gene, mainDB, logDB)
+	removed, err := chain.Rewind(uint32(to))
+	if err != nil {
+		return err
+	}
+	if err := logDB.Truncate(uint32(to)); err != nil {
+		return errors.WithMessage(err, "truncate logs")
+	}
+	best := chain.BestBlock().Header()
+	fmt.Printf("best block rewound to #%v %v, %v block(s) removed\n", best.Number(), best.ID(), len(removed))
+	return nil
+}
+
+func dbStatsAction(ctx *cli.Context) error {
+	initLogger(ctx)
+	gene := selectGenesis(ctx)
+	instanceDir := makeInstanceDir(ctx, gene)
+
+	mainDB := openMainDB(ctx, instanceDir)
+	defer mainDB.Close()
+
+	logDB := openLogDB(ctx, instanceDir)
+	defer logDB.Close()
+
+	fmt.Println("main database:")
+	if err := printKVStats(mainDB, func(key []byte) string {
+		if state.IsHistoryKey(key) {
+			return "state history"
+		}
+		if state.IsPreimageKey(key) {
+			return "preimages"
+		}
+		return chain.KeyCategory(key)
+	}); err != nil {
+		return errors.WithMessage(err, "main database")
+	}
+	levelStats, err := mainDB.Stats()
+	if err != nil {
+		return errors.WithMessage(err, "main database")
+	}
+	fmt.Println(levelStats)
+
+	stashDir := filepath.Join(instanceDir, "tx.stash")
+	if _, err := os.Stat(stashDir); err == nil {
+		stashDB, err := lvldb.New(stashDir, lvldb.Options{})
+		if err != nil {
+			return errors.WithMessage(err, "open tx stash")
+		}
+		defer stashDB.Close()
+
+		fmt.Println("tx stash:")
+		if err := printKVStats(stashDB, func([]byte) string { return "txs" }); err != nil {
+			return errors.WithMessage(err, "tx stash")
+		}
+		fmt.Println()
+	}
+
+	tables, total, err := logDB.Stats()
+	if err != nil {
+		return errors.WithMessage(err, "log database")
+	}
+	fmt.Println("log database:")
+	for _, t := range tables {
+		size := "n/a"
+		if t.Size >= 0 {
+			size = metric.StorageSize(t.Size).String()
+		}
+		fmt.Printf("  %-20v %12v %12v\n", t.Name, t.Rows, size)
+	}
+	fmt.Printf("  %-20v %12v %12v\n", "total", "", metric.StorageSize(total))
+	return nil
+}
+
+func dbCompactAction(ctx *cli.Context) error {
+	initLogger(ctx)
+	gene := selectGenesis(ctx)
+	instanceDir := makeInstanceDir(ctx, gene)
+
+	mainDB := openMainDB(ctx, instanceDir)
+	defer mainDB.Close()
+
+	logDB := openLogDB(ctx, instanceDir)
+	defer logDB.Close()
+
+	log.Info("compacting main database...")
+	if err := mainDB.Compact(kv.Range{}); err != nil {
+		return errors.WithMessage(err, "compact main database")
+	}
+	log.Info("vacuuming log database...")
+	if err := logDB.Vacuum(); err != nil {
+		return errors.WithMessage(err, "vacuum log database")
+	}
+	fmt.Println("databases compacted")
+	return nil
+}
+
+// printKVStats iterates all entries of the kv store, and prints counts and sizes grouped by category.
+func printKVStats(store kv.Getter, category func(key []byte) string) error {
+	type entryStats struct {
+		count int
+		size  metric.StorageSize
+	}
+	var (
+		stats = make(map[string]*entryStats)
+		total entryStats
+	)
+
+	it := store.NewIterator(kv.Range{})
+	defer it.Release()
+	for it.Next() {
+		name := category(it.Key())
+		s := stats[name]
+		if s == nil {
+			s = &entryStats{}
+			stats[name] = s
+		}
+		size := metric.StorageSize(len(it.Key()) + len(it.Value()))
+		s.count++
+		s.size += size
+		total.count++
+		total.size += size
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("  %-20v %12v %12v\n", name, stats[name].count, stats[name].size)
+	}
+	fmt.Printf("  %-20v %12v %12v\n", "total", total.count, total.size)
+	return nil
+}
+
+// checkTrunk walks through trunk blocks from the best one down to genesis.
+// States of blocks within stateDepth from the best block are verified.
+func checkTrunk(mainDB *lvldb.LevelDB, logDB *logdb.LogDB, genesisID powerplay.Bytes32, stateDepth uint32) (*trunkCheck, error) {
+	verifier := chain.NewVerifier(mainDB)
+	stateVerifier := state.NewVerifier(mainDB)
+
+	bestID, err := verifier.BestBlockID()
+	if err != nil {
+		return nil, errors.WithMessage(err, "load best block ID")
+	}
+
+	check := &trunkCheck{bestID: bestID, headID: bestID}
+	bestNum := block.Number(bestID)
+
+	for id := bestID; ; {
+		num := block.Number(id)
+		if num%10000 == 0 {
+			log.Info("verifying blocks", "number", num)
+		}
+
+		header, err := verifier.VerifyBlock(id)
+		if err == nil && num > 0 {
+			err = checkLogs(verifier, logDB, id)
+		}
+		if err == nil && bestNum-num < stateDepth {
+			if err = stateVerifier.Verify(header.StateRoot()); err != nil {
+				err = errors.WithMessage(err, "verify state")
+			}
+		}
+		if err != nil {
+			check.problems++
+			fmt.Printf("#%v %v: %v\n", num, id, err)
+		}
+
+		if num == 0 {
+			if id != genesisID {
+				return nil, errors.New("genesis mismatch")
+			}
+			return check, nil
+		}
+
+		parentID, perr := trunkParentID(verifier, bestID, id, header)
+		if perr != nil {
+			return nil, perr
+		}
+		if err != nil {
+			check.headID = parentID
+		}
+		id = parentID
+	}
+}
+
+// checkLogs checks that logs in log db match receipts of the block.
+func checkLogs(verifier *chain.Verifier, logDB *logdb.LogDB, blockID powerplay.Bytes32) error {
+	receipts, err := verifier.GetBlockReceipts(blockID)
+	if err != nil {
+		return errors.WithMessage(err, "load receipts")
+	}
+	var nEvents, nTransfers int
+	for _, r := range receipts {
+		for _, o := range r.Outputs {
+			nEvents += len(o.Events)
+			nTransfers += len(o.Transfers)
+		}
+	}
+	events, transfers, err := logDB.CountByBlock(blockID)
+	if err != nil {
+		return errors.WithMessage(err, "count logs")
+	}
+	if events != nEvents || transfers != nTransfers {
+		return fmt.Errorf("logs mismatch: events %v/%v, transfers %v/%v", events, nEvents, transfers, nTransfers)
+	}
+	return nil
+}
+
+// trunkParentID returns parent ID of the trunk block.
+// It falls back to number index of best block, if the block header is not available.
+func trunkParentID(verifier *chain.Verifier, bestID, id powerplay.Bytes32, header *block.Header) (powerplay.Bytes32, error) {
+	if header != nil {
+		return header.ParentID(), nil
+	}
+	num := block.Number(id)
+	parentID, err := verifier.GetAncestor(bestID, num-1)
+	if err != nil {
+		return powerplay.Bytes32{}, errors.WithMessage(err, fmt.Sprintf("trunk broken at #%v", num))
+	}
+	return parentID, nil
+}
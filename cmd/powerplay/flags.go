@@ -8,13 +8,14 @@ package main
 
 import (
 	"github.com/inconshreveable/log15"
+	"github.com/playmakerchain/powerplay/keystore"
 	cli "gopkg.in/urfave/cli.v1"
 )
 
 var (
 	networkFlag = cli.StringFlag{
 		Name:  "network",
-		Usage: "the network to join (main|test)",
+		Usage: "the network to join (main|test) or path to genesis json file",
 	}
 	configDirFlag = cli.StringFlag{
 		Name:   "config-dir",
@@ -101,6 +102,152 @@ var (
 	targetGasLimitFlag = cli.IntFlag{
 		Name:  "target-gas-limit",
 		Value: 0,
-		Usage: "target block gas limit (adaptive if set to 0)",
+		Usage: "target block gas limit (adaptive if set to 0, see min/max-target-gas-limit)",
+	}
+	stateDepthFlag = cli.IntFlag{
+		Name:  "state-depth",
+		Value: 16,
+		Usage: "number of recent blocks whose states are verified",
+	}
+	rewindToFlag = cli.IntFlag{
+		Name:  "to",
+		Value: -1,
+		Usage: "number of the block to rewind to",
+	}
+	apiAdminFlag = cli.BoolFlag{
+		Name:  "api-admin",
+		Usage: "enable admin API, e.g. chain rewinding and block production dry run",
+	}
+	archiveFlag = cli.BoolFlag{
+		Name:  "archive",
+		Usage: "record state history, to serve account queries at any trunk block",
+	}
+	preimagesFlag = cli.BoolFlag{
+		Name:  "preimages",
+		Usage: "record preimages of SHA3 and storage keys, to be queried via debug API",
+	}
+	txPoolLocalsFlag = cli.BoolFlag{
+		Name:  "txpool-locals",
+		Usage: "prioritize txs submitted via API in tx pool, and exempt them from eviction",
+	}
+	txPoolPriorityOriginsFlag = cli.StringFlag{
+		Name:  "txpool-priority-origins",
+		Usage: "comma separated list of origin addresses whose txs are prioritized and never evicted from tx pool",
+	}
+	txPoolPriorityToFlag = cli.StringFlag{
+		Name:  "txpool-priority-to",
+		Usage: "comma separated list of contract addresses, txs calling which are prioritized and never evicted from tx pool",
+	}
+	txOriginFlag = cli.StringFlag{
+		Name:  "origin",
+		Usage: "address of the account to sign the tx",
+	}
+	txWorkFlag = cli.StringFlag{
+		Name:  "work",
+		Usage: "target work in decimal",
+	}
+	txWorkGasFlag = cli.Uint64Flag{
+		Name:  "work-gas",
+		Usage: "amount of gas to be exchanged by work, which raises overall gas price by base gas price * work-gas / gas",
+	}
+	txThreadsFlag = cli.IntFlag{
+		Name:  "threads",
+		Usage: "number of threads to search work (number of CPUs if set to 0)",
+	}
+	txAPIURLFlag = cli.StringFlag{
+		Name:  "api-url",
+		Value: "http://localhost:2843",
+		Usage: "URL of the node's API service",
+	}
+	txGasFlag = cli.Uint64Flag{
+		Name:  "gas",
+		Usage: "gas limit of the tx (intrinsic gas if set to 0)",
+	}
+	txGasPriceCoefFlag = cli.Uint64Flag{
+		Name:  "gas-price-coef",
+		Usage: "gas price coefficient of the tx (0-255)",
+	}
+	txExpirationFlag = cli.Uint64Flag{
+		Name:  "expiration",
+		Value: 720,
+		Usage: "number of blocks the tx remains valid after block ref",
+	}
+	txNonceFlag = cli.Uint64Flag{
+		Name:  "nonce",
+		Usage: "nonce of the tx (random if not set)",
+	}
+	txDependsOnFlag = cli.StringFlag{
+		Name:  "depends-on",
+		Usage: "ID of the tx this tx depends on",
+	}
+	accountFlag = cli.StringFlag{
+		Name:  "account",
+		Usage: "address of the keystore account to use instead of master key",
+	}
+	passwordFileFlag = cli.StringFlag{
+		Name:  "password-file",
+		Usage: "file containing the passphrase to unlock keystore account",
+	}
+	derivationPathFlag = cli.StringFlag{
+		Name:  "derivation-path",
+		Value: keystore.DefaultDerivationPath,
+		Usage: "BIP32 path to derive account from mnemonic, with account index appended",
+	}
+	accountIndexFlag = cli.Uint64Flag{
+		Name:  "index",
+		Usage: "index of the account derived from mnemonic",
+	}
+	signerFlag = cli.StringFlag{
+		Name:  "signer",
+		Usage: "endpoint of remote block signer, e.g. unix:///path/to/signer.sock or http://localhost:2850",
+	}
+	signerListenFlag = cli.StringFlag{
+		Name:  "listen",
+		Usage: "endpoint for the signer to listen on (defaults to unix socket 'signer.sock' in config dir)",
+	}
+	signerTokenFileFlag = cli.StringFlag{
+		Name:  "signer-token-file",
+		Usage: "file holding the secret token shared by the signer and nodes, required for http signer endpoints",
+	}
+	proposerIndexFlag = cli.BoolFlag{
+		Name:  "proposer-index",
+		Usage: "index produced and missed slots of proposers, to be queried via node API",
+	}
+	leaseFileFlag = cli.StringFlag{
+		Name:  "lease-file",
+		Usage: "signing lease file shared by nodes with the same master key, only the holder packs blocks",
+	}
+	standbyOfFlag = cli.StringFlag{
+		Name:  "standby-of",
+		Usage: "API URL of the primary node, to pack blocks only when the primary stops packing (requires --signer shared with the primary)",
+	}
+	leaseTTLFlag = cli.IntFlag{
+		Name:  "lease-ttl",
+		Value: 30,
+		Usage: "seconds before an unrenewed signing lease expires",
+	}
+	txStrategyFlag = cli.StringFlag{
+		Name:  "tx-strategy",
+		Value: "pool",
+		Usage: "order of txs to be packed (pool|maxfee|fair|fifo), txs prioritized by txpool-priority-* flags come first",
+	}
+	minTargetGasLimitFlag = cli.IntFlag{
+		Name:  "min-target-gas-limit",
+		Value: 0,
+		Usage: "lower bound of adaptive target block gas limit (defaults to the protocol minimum)",
+	}
+	maxTargetGasLimitFlag = cli.IntFlag{
+		Name:  "max-target-gas-limit",
+		Value: 0,
+		Usage: "upper bound of adaptive target block gas limit (unbounded if set to 0)",
+	}
+	proposerSlotsFlag = cli.IntFlag{
+		Name:  "slots",
+		Value: 5,
+		Usage: "number of next slots to preview",
+	}
+	dryRunFlag = cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "build a block with pending txs, without signing or broadcasting it (requires --api-admin on the node)",
 	}
 )
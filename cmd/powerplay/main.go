@@ -21,14 +21,18 @@ import (
 	"github.com/pborman/uuid"
 	"github.com/pkg/errors"
 	"github.com/playmakerchain/powerplay/api"
+	"github.com/playmakerchain/powerplay/api/admin"
 	"github.com/playmakerchain/powerplay/cmd/powerplay/node"
 	"github.com/playmakerchain/powerplay/cmd/powerplay/solo"
+	"github.com/playmakerchain/powerplay/equivocation"
 	"github.com/playmakerchain/powerplay/genesis"
 	"github.com/playmakerchain/powerplay/logdb"
 	"github.com/playmakerchain/powerplay/lvldb"
+	"github.com/playmakerchain/powerplay/proposers"
 	"github.com/playmakerchain/powerplay/state"
 	"github.com/playmakerchain/powerplay/powerplay"
 	"github.com/playmakerchain/powerplay/txpool"
+	"github.com/playmakerchain/powerplay/txtracker"
 	cli "gopkg.in/urfave/cli.v1"
 )
 
@@ -42,6 +46,7 @@ var (
 		Limit:           10000,
 		LimitPerAccount: 16,
 		MaxLifetime:     20 * time.Minute,
+		JournalRotation: time.Hour,
 	}
 )
 
@@ -70,6 +75,23 @@ func main() {
 			apiTimeoutFlag,
 			apiCallGasLimitFlag,
 			apiBacktraceLimitFlag,
+			apiAdminFlag,
+			archiveFlag,
+			preimagesFlag,
+			proposerIndexFlag,
+			accountFlag,
+			passwordFileFlag,
+			signerFlag,
+			signerTokenFileFlag,
+			leaseFileFlag,
+			standbyOfFlag,
+			leaseTTLFlag,
+			txStrategyFlag,
+			minTargetGasLimitFlag,
+			maxTargetGasLimitFlag,
+			txPoolLocalsFlag,
+			txPoolPriorityOriginsFlag,
+			txPoolPriorityToFlag,
 			verbosityFlag,
 			maxPeersFlag,
 			p2pPortFlag,
@@ -104,6 +126,189 @@ func main() {
 				},
 				Action: masterKeyAction,
 			},
+			{
+				Name:  "account",
+				Usage: "manage encrypted accounts in keystore",
+				Subcommands: []cli.Command{
+					{
+						Name:   "list",
+						Usage:  "list accounts in keystore",
+						Flags:  []cli.Flag{configDirFlag},
+						Action: accountListAction,
+					},
+					{
+						Name:  "new",
+						Usage: "create a new account",
+						Flags: []cli.Flag{
+							configDirFlag,
+							passwordFileFlag,
+						},
+						Action: accountNewAction,
+					},
+					{
+						Name:  "import",
+						Usage: "import an account derived from BIP39 mnemonic read from stdin",
+						Flags: []cli.Flag{
+							configDirFlag,
+							passwordFileFlag,
+							derivationPathFlag,
+							accountIndexFlag,
+						},
+						Action: accountImportAction,
+					},
+				},
+			},
+			{
+				Name:  "signer",
+				Usage: "serve the block signing key to nodes through local socket, with double sign protection",
+				Flags: []cli.Flag{
+					configDirFlag,
+					accountFlag,
+					passwordFileFlag,
+					signerListenFlag,
+					signerTokenFileFlag,
+					verbosityFlag,
+				},
+				Action: signerAction,
+			},
+			{
+				Name:  "rewind",
+				Usage: "reset best block to an earlier trunk block",
+				Flags: []cli.Flag{
+					networkFlag,
+					dataDirFlag,
+					rewindToFlag,
+					verbosityFlag,
+				},
+				Action: rewindAction,
+			},
+			{
+				Name:  "db",
+				Usage: "verify, repair and maintain databases",
+				Subcommands: []cli.Command{
+					{
+						Name:  "verify",
+						Usage: "check integrity of chain data",
+						Flags: []cli.Flag{
+							networkFlag,
+							dataDirFlag,
+							stateDepthFlag,
+							verbosityFlag,
+						},
+						Action: dbVerifyAction,
+					},
+					{
+						Name:  "repair",
+						Usage: "rewind best block to the last consistent one",
+						Flags: []cli.Flag{
+							networkFlag,
+							dataDirFlag,
+							stateDepthFlag,
+							verbosityFlag,
+						},
+						Action: dbRepairAction,
+					},
+					{
+						Name:  "stats",
+						Usage: "show entry counts and sizes of databases",
+						Flags: []cli.Flag{
+							networkFlag,
+							dataDirFlag,
+							verbosityFlag,
+						},
+						Action: dbStatsAction,
+					},
+					{
+						Name:  "compact",
+						Usage: "compact main database and vacuum log database",
+						Flags: []cli.Flag{
+							networkFlag,
+							dataDirFlag,
+							verbosityFlag,
+						},
+						Action: dbCompactAction,
+					},
+				},
+			},
+			{
+				Name:  "tx",
+				Usage: "transaction utilities",
+				Subcommands: []cli.Command{
+					{
+						Name:      "build",
+						Usage:     "build an unsigned tx from a JSON array of clauses with fields 'to', 'value' and 'data'",
+						ArgsUsage: "<clauses-json|->",
+						Flags: []cli.Flag{
+							txAPIURLFlag,
+							txGasFlag,
+							txGasPriceCoefFlag,
+							txExpirationFlag,
+							txNonceFlag,
+							txDependsOnFlag,
+							verbosityFlag,
+						},
+						Action: txBuildAction,
+					},
+					{
+						Name:      "sign",
+						Usage:     "sign the raw tx with master key or keystore account",
+						ArgsUsage: "<raw-tx|->",
+						Flags: []cli.Flag{
+							configDirFlag,
+							accountFlag,
+							passwordFileFlag,
+							verbosityFlag,
+						},
+						Action: txSignAction,
+					},
+					{
+						Name:      "send",
+						Usage:     "send the signed raw tx to the node",
+						ArgsUsage: "<raw-tx|->",
+						Flags: []cli.Flag{
+							txAPIURLFlag,
+							verbosityFlag,
+						},
+						Action: txSendAction,
+					},
+					{
+						Name:      "decode",
+						Usage:     "decode and print the raw tx",
+						ArgsUsage: "<raw-tx|->",
+						Action:    txDecodeAction,
+					},
+					{
+						Name:      "work",
+						Usage:     "search a nonce with work to raise overall gas price of the unsigned tx",
+						ArgsUsage: "<raw-tx|->",
+						Flags: []cli.Flag{
+							txOriginFlag,
+							txWorkFlag,
+							txWorkGasFlag,
+							txThreadsFlag,
+							verbosityFlag,
+						},
+						Action: txWorkAction,
+					},
+				},
+			},
+			{
+				Name:  "proposer",
+				Usage: "block proposer utilities",
+				Subcommands: []cli.Command{
+					{
+						Name:  "status",
+						Usage: "check whether the node master is endorsed, and preview its next slots",
+						Flags: []cli.Flag{
+							txAPIURLFlag,
+							proposerSlotsFlag,
+							dryRunFlag,
+							verbosityFlag,
+						},
+						Action: proposerStatusAction,
+					},
+				},
+			},
 		},
 	}
 
@@ -129,33 +334,81 @@ func defaultAction(ctx *cli.Context) error {
 	defer func() { log.Info("closing log database..."); logDB.Close() }()
 
 	chain := initChain(gene, mainDB, logDB)
-	master := loadNodeMaster(ctx)
+	master := loadNodeMaster(ctx, mainDB)
 
-	txPool := txpool.New(chain, state.NewCreator(mainDB), defaultTxPoolOptions)
+	txPoolOptions := defaultTxPoolOptions
+	txPoolOptions.Journal = filepath.Join(instanceDir, "tx.journal")
+	txPoolOptions.Policy = txPoolPolicy(ctx)
+	txPool := txpool.New(chain, state.NewCreator(mainDB), txPoolOptions)
 	defer func() { log.Info("closing tx pool..."); txPool.Close() }()
 
-	p2pcom := newP2PComm(ctx, chain, txPool, instanceDir)
-	apiHandler, apiCloser := api.New(chain, state.NewCreator(mainDB), txPool, logDB, p2pcom.comm, ctx.String(apiCorsFlag.Name), uint32(ctx.Int(apiBacktraceLimitFlag.Name)), uint64(ctx.Int(apiCallGasLimitFlag.Name)))
-	defer func() { log.Info("closing API..."); apiCloser() }()
+	txTracker := txtracker.New(chain, txPool)
+	defer func() { log.Info("closing tx tracker..."); txTracker.Close() }()
 
-	apiURL, srvCloser := startAPIServer(ctx, apiHandler, chain.GenesisBlock().Header().ID())
-	defer func() { log.Info("stopping API server..."); srvCloser() }()
+	var history *state.History
+	if ctx.Bool(archiveFlag.Name) {
+		history = state.NewHistory(mainDB)
+	}
 
-	printStartupMessage(gene, chain, master, instanceDir, apiURL)
+	stateCreator := state.NewCreator(mainDB)
+	stateCreator.SetPreimageRecording(ctx.Bool(preimagesFlag.Name))
 
-	p2pcom.Start()
-	defer p2pcom.Stop()
+	var proposerIndex *proposers.Index
+	if ctx.Bool(proposerIndexFlag.Name) {
+		proposerDB := openProposerDB(instanceDir)
+		defer func() { log.Info("closing proposer database..."); proposerDB.Close() }()
+		proposerIndex = proposers.New(proposerDB, chain, state.NewCreator(mainDB))
+	}
+
+	evidencePool := equivocation.NewPool(mainDB)
 
-	return node.New(
+	p2pcom := newP2PComm(ctx, chain, stateCreator, txPool, instanceDir)
+	powerplayNode := node.New(
 		master,
 		chain,
-		state.NewCreator(mainDB),
+		stateCreator,
 		logDB,
 		txPool,
 		filepath.Join(instanceDir, "tx.stash"),
 		p2pcom.comm,
-		uint64(ctx.Int(targetGasLimitFlag.Name))).
-		Run(exitSignal)
+		node.Options{
+			History:        history,
+			ProposerIndex:  proposerIndex,
+			EvidencePool:   evidencePool,
+			TargetGasLimit: uint64(ctx.Int(targetGasLimitFlag.Name)),
+			GasLimitCtl:    gasLimitController(ctx),
+			TxStrategy:     txStrategy(ctx),
+			Lease:          makeLease(ctx),
+		})
+
+	var rewinder admin.Rewinder
+	if ctx.Bool(apiAdminFlag.Name) {
+		rewinder = powerplayNode
+	}
+	apiHandler, apiCloser := api.New(chain, state.NewCreator(mainDB), txPool, txTracker, logDB, p2pcom.comm, api.Options{
+		History:        history,
+		ProposerIndex:  proposerIndex,
+		EvidencePool:   evidencePool,
+		Leaser:         powerplayNode,
+		GasLimiter:     powerplayNode,
+		Producer:       powerplayNode,
+		Rewinder:       rewinder,
+		AllowDryRun:    ctx.Bool(apiAdminFlag.Name),
+		AllowedOrigins: ctx.String(apiCorsFlag.Name),
+		BacktraceLimit: uint32(ctx.Int(apiBacktraceLimitFlag.Name)),
+		CallGasLimit:   uint64(ctx.Int(apiCallGasLimitFlag.Name)),
+	})
+	defer func() { log.Info("closing API..."); apiCloser() }()
+
+	apiURL, srvCloser := startAPIServer(ctx, apiHandler, chain.GenesisBlock().Header().ID())
+	defer func() { log.Info("stopping API server..."); srvCloser() }()
+
+	printStartupMessage(gene, chain, master, instanceDir, apiURL)
+
+	p2pcom.Start()
+	defer p2pcom.Stop()
+
+	return powerplayNode.Run(exitSignal)
 }
 
 func soloAction(ctx *cli.Context) error {
@@ -183,10 +436,21 @@ func soloAction(ctx *cli.Context) error {
 
 	chain := initChain(gene, mainDB, logDB)
 
-	txPool := txpool.New(chain, state.NewCreator(mainDB), defaultTxPoolOptions)
+	txPoolOptions := defaultTxPoolOptions
+	if ctx.Bool("persist") {
+		txPoolOptions.Journal = filepath.Join(instanceDir, "tx.journal")
+	}
+	txPool := txpool.New(chain, state.NewCreator(mainDB), txPoolOptions)
 	defer func() { log.Info("closing tx pool..."); txPool.Close() }()
 
-	apiHandler, apiCloser := api.New(chain, state.NewCreator(mainDB), txPool, logDB, solo.Communicator{}, ctx.String(apiCorsFlag.Name), uint32(ctx.Int(apiBacktraceLimitFlag.Name)), uint64(ctx.Int(apiCallGasLimitFlag.Name)))
+	txTracker := txtracker.New(chain, txPool)
+	defer func() { log.Info("closing tx tracker..."); txTracker.Close() }()
+
+	apiHandler, apiCloser := api.New(chain, state.NewCreator(mainDB), txPool, txTracker, logDB, solo.Communicator{}, api.Options{
+		AllowedOrigins: ctx.String(apiCorsFlag.Name),
+		BacktraceLimit: uint32(ctx.Int(apiBacktraceLimitFlag.Name)),
+		CallGasLimit:   uint64(ctx.Int(apiCallGasLimitFlag.Name)),
+	})
 	defer func() { log.Info("closing API..."); apiCloser() }()
 
 	apiURL, srvCloser := startAPIServer(ctx, apiHandler, chain.GenesisBlock().Header().ID())
@@ -7,6 +7,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"math/rand"
@@ -14,6 +15,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -28,9 +30,12 @@ import (
 	"github.com/playmakerchain/powerplay/co"
 	"github.com/playmakerchain/powerplay/comm"
 	"github.com/playmakerchain/powerplay/genesis"
+	"github.com/playmakerchain/powerplay/kv"
 	"github.com/playmakerchain/powerplay/logdb"
 	"github.com/playmakerchain/powerplay/lvldb"
 	"github.com/playmakerchain/powerplay/p2psrv"
+	"github.com/playmakerchain/powerplay/packer"
+	"github.com/playmakerchain/powerplay/signer"
 	"github.com/playmakerchain/powerplay/state"
 	"github.com/playmakerchain/powerplay/powerplay"
 	"github.com/playmakerchain/powerplay/txpool"
@@ -54,6 +59,11 @@ func selectGenesis(ctx *cli.Context) *genesis.Genesis {
 	case "main":
 		return genesis.NewMainnet()
 	default:
+		if network != "" {
+			if _, err := os.Stat(network); err == nil {
+				return loadCustomGenesis(network)
+			}
+		}
 		cli.ShowAppHelp(ctx)
 		if network == "" {
 			fmt.Printf("network flag not specified: -%s\n", networkFlag.Name)
@@ -65,6 +75,26 @@ func selectGenesis(ctx *cli.Context) *genesis.Genesis {
 	}
 }
 
+// loadCustomGenesis loads custom network from genesis json file, and registers its fork config.
+func loadCustomGenesis(path string) *genesis.Genesis {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		fatal(fmt.Sprintf("read genesis file [%v]: %v", path, err))
+	}
+	var gen genesis.CustomGenesis
+	if err := json.Unmarshal(data, &gen); err != nil {
+		fatal(fmt.Sprintf("decode genesis file [%v]: %v", path, err))
+	}
+	gene, err := genesis.NewCustomNet(&gen)
+	if err != nil {
+		fatal(fmt.Sprintf("build custom genesis: %v", err))
+	}
+	if gen.ForkConfig != nil {
+		powerplay.RegisterForkConfig(gene.ID(), *gen.ForkConfig)
+	}
+	return gene
+}
+
 func makeConfigDir(ctx *cli.Context) string {
 	configDir := ctx.String(configDirFlag.Name)
 	if configDir == "" {
@@ -122,6 +152,15 @@ func openMainDB(ctx *cli.Context, dataDir string) *lvldb.LevelDB {
 	return db
 }
 
+func openProposerDB(dataDir string) *lvldb.LevelDB {
+	dir := filepath.Join(dataDir, "proposers.db")
+	db, err := lvldb.New(dir, lvldb.Options{})
+	if err != nil {
+		fatal(fmt.Sprintf("open proposer database [%v]: %v", dir, err))
+	}
+	return db
+}
+
 func openLogDB(ctx *cli.Context, dataDir string) *logdb.LogDB {
 	dir := filepath.Join(dataDir, "logs.db")
 	db, err := logdb.New(dir)
@@ -167,31 +206,129 @@ func beneficiary(ctx *cli.Context) *powerplay.Address {
 	return &addr
 }
 
-func loadNodeMaster(ctx *cli.Context) *node.Master {
+func parseAddressSet(ctx *cli.Context, flag cli.StringFlag) map[powerplay.Address]bool {
+	set := make(map[powerplay.Address]bool)
+	for _, value := range strings.Split(ctx.String(flag.Name), ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		addr, err := powerplay.ParseAddress(value)
+		if err != nil {
+			fatal(fmt.Sprintf("invalid address in %s:", flag.Name), err)
+		}
+		set[addr] = true
+	}
+	return set
+}
+
+func txPoolPolicy(ctx *cli.Context) txpool.Policy {
+	return &txpool.PriorityPolicy{
+		Locals:  ctx.Bool(txPoolLocalsFlag.Name),
+		Origins: parseAddressSet(ctx, txPoolPriorityOriginsFlag),
+		To:      parseAddressSet(ctx, txPoolPriorityToFlag),
+	}
+}
+
+func txStrategy(ctx *cli.Context) packer.Strategy {
+	strategy, err := packer.NewStrategy(ctx.String(txStrategyFlag.Name))
+	if err != nil {
+		fatal(err)
+	}
+	if _, ok := strategy.(packer.PoolOrderStrategy); ok {
+		// already prioritized by pool policy
+		return strategy
+	}
+	origins := parseAddressSet(ctx, txPoolPriorityOriginsFlag)
+	to := parseAddressSet(ctx, txPoolPriorityToFlag)
+	if len(origins) == 0 && len(to) == 0 {
+		return strategy
+	}
+	return &packer.PriorityStrategy{
+		Origins: origins,
+		To:      to,
+		Next:    strategy,
+	}
+}
+
+// gasLimitController returns nil if target gas limit is fixed.
+func gasLimitController(ctx *cli.Context) *packer.GasLimitController {
+	if ctx.Int(targetGasLimitFlag.Name) != 0 {
+		return nil
+	}
+	min, max := ctx.Int(minTargetGasLimitFlag.Name), ctx.Int(maxTargetGasLimitFlag.Name)
+	if min < 0 || max < 0 {
+		fatal("negative target gas limit bound")
+	}
+	if max != 0 && max < min {
+		fatal("max-target-gas-limit less than min-target-gas-limit")
+	}
+	return packer.NewGasLimitController(uint64(min), uint64(max))
+}
+
+func loadNodeMaster(ctx *cli.Context, guardStore kv.GetPutter) *node.Master {
 	if ctx.String(networkFlag.Name) == "dev" {
 		i := rand.Intn(len(genesis.DevAccounts()))
 		acc := genesis.DevAccounts()[i]
 		return &node.Master{
-			PrivateKey:  acc.PrivateKey,
+			Signer:      signer.NewKeySigner(acc.PrivateKey),
 			Beneficiary: beneficiary(ctx),
 		}
 	}
-	key, err := loadOrGeneratePrivateKey(masterKeyPath(ctx))
-	if err != nil {
-		fatal("load or generate master key:", err)
+	var s signer.Signer
+	if endpoint := ctx.String(signerFlag.Name); endpoint != "" {
+		token, err := readSignerToken(ctx, endpoint)
+		if err != nil {
+			fatal(err)
+		}
+		remote, err := signer.NewRemote(endpoint, token)
+		if err != nil {
+			fatal("connect remote signer:", err)
+		}
+		s = remote
+	} else {
+		key, err := loadSigningKey(ctx)
+		if err != nil {
+			fatal(err)
+		}
+		s = signer.NewKeySigner(key)
 	}
-	master := &node.Master{PrivateKey: key}
+	master := &node.Master{Signer: signer.NewGuard(s, guardStore)}
 	master.Beneficiary = beneficiary(ctx)
 	return master
 }
 
+// makeLease creates signing lease for nodes sharing the same master key, or nil if not configured.
+func makeLease(ctx *cli.Context) node.Lease {
+	leaseFile, standbyOf := ctx.String(leaseFileFlag.Name), ctx.String(standbyOfFlag.Name)
+	if leaseFile != "" && standbyOf != "" {
+		fatal(fmt.Sprintf("flag %s and %s are exclusive", leaseFileFlag.Name, standbyOfFlag.Name))
+	}
+	ttl := time.Duration(ctx.Int(leaseTTLFlag.Name)) * time.Second
+	if ttl <= 0 {
+		fatal("invalid lease ttl")
+	}
+	switch {
+	case leaseFile != "":
+		return node.NewFileLease(leaseFile, ttl)
+	case standbyOf != "":
+		// an unreachable primary can't be told from a dead one, so it's the shared signer
+		// that finally prevents both nodes from signing at the same height
+		if ctx.String(signerFlag.Name) == "" {
+			fatal(fmt.Sprintf("flag %s requires %s, the remote signer shared with the primary", standbyOfFlag.Name, signerFlag.Name))
+		}
+		return node.NewHeartbeatLease(standbyOf, ttl)
+	}
+	return nil
+}
+
 type p2pComm struct {
 	comm           *comm.Communicator
 	p2pSrv         *p2psrv.Server
 	peersCachePath string
 }
 
-func newP2PComm(ctx *cli.Context, chain *chain.Chain, txPool *txpool.TxPool, instanceDir string) *p2pComm {
+func newP2PComm(ctx *cli.Context, chain *chain.Chain, stateCreator *state.Creator, txPool *txpool.TxPool, instanceDir string) *p2pComm {
 	configDir := makeConfigDir(ctx)
 	key, err := loadOrGeneratePrivateKey(filepath.Join(configDir, "p2p.key"))
 	if err != nil {
@@ -224,7 +361,7 @@ func newP2PComm(ctx *cli.Context, chain *chain.Chain, txPool *txpool.TxPool, ins
 	}
 
 	return &p2pComm{
-		comm:           comm.New(chain, txPool),
+		comm:           comm.New(chain, stateCreator, txPool),
 		p2pSrv:         p2psrv.New(opts),
 		peersCachePath: peersCachePath,
 	}
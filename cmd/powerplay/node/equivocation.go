@@ -0,0 +1,55 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package node
+
+import (
+	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/builtin"
+	"github.com/playmakerchain/powerplay/equivocation"
+)
+
+// checkEquivocation checks the header against recently seen ones, and reports evidence if found.
+func (n *Node) checkEquivocation(header *block.Header) {
+	ev, err := n.detector.Check(header)
+	if err != nil || ev == nil {
+		return
+	}
+	if err := n.reportEvidence(ev); err != nil {
+		log.Debug("failed to report evidence", "evidence", ev, "err", err)
+	}
+}
+
+// reportEvidence saves and broadcasts the evidence, if it's new and the offender is a listed authority.
+func (n *Node) reportEvidence(ev *equivocation.Evidence) error {
+	if err := ev.Verify(); err != nil {
+		return err
+	}
+	offender, err := ev.Offender()
+	if err != nil {
+		return err
+	}
+
+	st, err := n.stateCreator.NewState(n.chain.BestBlock().Header().StateRoot())
+	if err != nil {
+		return err
+	}
+	listed, _, _, _ := builtin.Authority.Native(st).Get(offender)
+	if err := st.Err(); err != nil {
+		return err
+	}
+	if !listed {
+		return errors.New("offender not listed")
+	}
+
+	added, err := n.evidencePool.Add(ev)
+	if err != nil || !added {
+		return err
+	}
+	log.Warn("equivocation detected", "offender", offender, "a", ev.A.ID(), "b", ev.B.ID())
+	n.comm.BroadcastEvidence(ev)
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package node
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/playmakerchain/powerplay/bft"
+	"github.com/playmakerchain/powerplay/cache"
+	"github.com/playmakerchain/powerplay/comm"
+)
+
+// finalityLoop exchanges finality votes with peers, votes on best block if the master is a voter,
+// and finalizes blocks on chain.
+func (n *Node) finalityLoop(ctx context.Context) {
+	log.Debug("enter finality loop")
+	defer log.Debug("leave finality loop")
+
+	var scope event.SubscriptionScope
+	defer scope.Close()
+
+	voteCh := make(chan *comm.NewVoteEvent)
+	scope.Track(n.comm.SubscribeVote(voteCh))
+
+	// votes on blocks not received yet, keyed by voter.
+	// only voters' votes reach here, and each voter keeps the latest one, so the cache can't be flooded
+	pendingVotes := cache.NewRandCache(256)
+
+	ticker := n.chain.NewTicker()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-voteCh:
+			if err := n.addVote(ev.Vote); err != nil {
+				if n.chain.IsNotFound(err) {
+					if signer, err := ev.Vote.Signer(); err == nil {
+						pendingVotes.Set(signer, ev.Vote)
+					}
+				} else if !bft.IsKnownVote(err) {
+					log.Debug("vote rejected", "vote", ev.Vote, "err", err)
+				}
+				continue
+			}
+		case <-ticker.C():
+			var entries []*cache.Entry
+			pendingVotes.ForEach(func(ent *cache.Entry) bool {
+				entries = append(entries, ent)
+				return true
+			})
+			for _, ent := range entries {
+				if err := n.addVote(ent.Value.(*bft.Vote)); err == nil || !n.chain.IsNotFound(err) {
+					pendingVotes.Remove(ent.Key)
+				}
+			}
+			if err := n.vote(); err != nil {
+				log.Warn("failed to vote", "err", err)
+			}
+		}
+
+		finalized, err := n.bft.Update()
+		if err != nil {
+			log.Warn("failed to update finality", "err", err)
+			continue
+		}
+		if finalized != nil {
+			log.Info("block finalized", "number", finalized.Number(), "id", finalized.ID())
+		}
+	}
+}
+
+// addVote adds the vote into bft engine, and relays it once accepted.
+func (n *Node) addVote(vote *bft.Vote) error {
+	if err := n.bft.AddVote(vote); err != nil {
+		return err
+	}
+	n.comm.BroadcastVote(vote)
+	return nil
+}
+
+// vote signs and broadcasts a vote on best block, if the master is an active voter.
+func (n *Node) vote() error {
+	// never vote before synced, or the vote would lag
+	select {
+	case <-n.comm.Synced():
+	default:
+		return nil
+	}
+	if !n.holdsLease() {
+		return nil
+	}
+
+	blockID, ok, err := n.bft.ShouldVote(n.master.Address())
+	if err != nil || !ok {
+		return err
+	}
+	vote := bft.NewVote(blockID)
+	sig, err := n.master.Signer.SignVote(vote)
+	if err != nil {
+		return err
+	}
+	vote = vote.WithSignature(sig)
+	log.Debug("voted", "vote", vote)
+	return n.addVote(vote)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package node
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/powerplay"
+)
+
+// historyLoop keeps state history index in line with trunk.
+func (n *Node) historyLoop(ctx context.Context) {
+	log.Debug("enter history loop")
+	defer log.Debug("leave history loop")
+
+	ticker := n.chain.NewTicker()
+	for {
+		if err := n.syncHistory(ctx); err != nil {
+			log.Warn("failed to record state history", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
+	}
+}
+
+// syncHistory reverts history records not on trunk, and records trunk blocks up to the best one.
+func (n *Node) syncHistory(ctx context.Context) error {
+	best := n.chain.BestBlock().Header()
+
+	var (
+		parentRoot powerplay.Bytes32
+		next       uint32
+	)
+	for {
+		headID, ok, err := n.history.HeadID()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		num := block.Number(headID)
+		if num <= best.Number() {
+			trunkID, err := n.chain.GetAncestorBlockID(best.ID(), num)
+			if err != nil {
+				return err
+			}
+			if trunkID == headID {
+				header, err := n.chain.GetBlockHeader(headID)
+				if err != nil {
+					return err
+				}
+				parentRoot, next = header.StateRoot(), num+1
+				break
+			}
+		}
+		if err := n.history.Revert(); err != nil {
+			return errors.WithMessage(err, "revert")
+		}
+		log.Debug("state history reverted", "id", headID)
+	}
+
+	startTime := time.Now()
+	for num := next; num <= best.Number(); num++ {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+		id, err := n.chain.GetAncestorBlockID(best.ID(), num)
+		if err != nil {
+			return err
+		}
+		header, err := n.chain.GetBlockHeader(id)
+		if err != nil {
+			return err
+		}
+		if err := n.history.Record(id, parentRoot, header.StateRoot()); err != nil {
+			return errors.WithMessage(err, "record")
+		}
+		parentRoot = header.StateRoot()
+
+		if time.Since(startTime) > 10*time.Second {
+			log.Info("recording state history", "number", num, "best", best.Number())
+			startTime = time.Now()
+		}
+	}
+	return nil
+}
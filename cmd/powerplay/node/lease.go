@@ -0,0 +1,260 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package node
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Lease arbitrates which one of the nodes sharing the same master key may sign blocks.
+type Lease interface {
+	// Renew acquires or renews the lease, reporting the highest block number signed by this node.
+	// It returns when the lease held expires by local time, zero if not held, and the highest block
+	// number signed by other holders.
+	Renew(signed uint32) (expires time.Time, othersSigned uint32, err error)
+	// Reserve records the block number about to be signed, before it's signed, so that others taking
+	// over the lease never sign at the height, even if this node stops right after signing.
+	Reserve(num uint32) error
+}
+
+// lockTimeout how long to wait for the lease lock when reserving a block number to sign.
+const lockTimeout = 500 * time.Millisecond
+
+type leaseRecord struct {
+	Holder  string `json:"holder"`
+	Expires int64  `json:"expires"` // unix time in milliseconds
+	Signed  uint32 `json:"signed"`
+}
+
+type fileLease struct {
+	path    string
+	holder  string
+	ttl     time.Duration
+	others  uint32
+	expires time.Time
+}
+
+// NewFileLease create a lease stored in a file shared by nodes.
+// The lease is held until it's not renewed within ttl.
+func NewFileLease(path string, ttl time.Duration) Lease {
+	var b [8]byte
+	rand.Read(b[:])
+	return &fileLease{
+		path:   path,
+		holder: hex.EncodeToString(b[:]),
+		ttl:    ttl,
+	}
+}
+
+func (l *fileLease) Renew(signed uint32) (time.Time, uint32, error) {
+	unlock, err := l.lock()
+	if err != nil {
+		return time.Time{}, l.others, err
+	}
+	if unlock == nil {
+		// another one is renewing, keep what's held until it expires
+		return l.expires, l.others, nil
+	}
+	defer unlock()
+
+	rec, err := l.read()
+	if err != nil {
+		return time.Time{}, l.others, err
+	}
+	// taken before writing, so the local expiry never goes beyond the recorded one
+	now := time.Now()
+	if rec != nil && rec.Holder != l.holder {
+		if rec.Signed > l.others {
+			l.others = rec.Signed
+		}
+		if now.UnixNano()/int64(time.Millisecond) < rec.Expires {
+			// held by another one
+			l.expires = time.Time{}
+			return l.expires, l.others, nil
+		}
+	}
+
+	if rec != nil && rec.Holder == l.holder && rec.Signed > signed {
+		// reserved but not yet signed, never lowered
+		signed = rec.Signed
+	}
+	expires := now.Add(l.ttl)
+	if err := l.write(&leaseRecord{
+		Holder:  l.holder,
+		Expires: expires.UnixNano() / int64(time.Millisecond),
+		Signed:  signed,
+	}); err != nil {
+		return time.Time{}, l.others, err
+	}
+	l.expires = expires
+	return l.expires, l.others, nil
+}
+
+func (l *fileLease) Reserve(num uint32) error {
+	unlock, err := l.lockWait(lockTimeout)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	rec, err := l.read()
+	if err != nil {
+		return err
+	}
+	if rec == nil || rec.Holder != l.holder || time.Now().UnixNano()/int64(time.Millisecond) >= rec.Expires {
+		return errLeaseNotHeld
+	}
+	if num <= rec.Signed {
+		return nil
+	}
+	rec.Signed = num
+	return l.write(rec)
+}
+
+// lockWait waits for the lock until timeout.
+func (l *fileLease) lockWait(timeout time.Duration) (unlock func(), err error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		unlock, err := l.lock()
+		if err != nil || unlock != nil {
+			return unlock, err
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.New("signing lease locked by another one")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// lock creates the lock file exclusively, so that only one node reads and updates the lease record
+// at a time. It returns nil unlock func if the lock is taken by another one. A lock file left by a
+// crashed node is removed once older than ttl.
+func (l *fileLease) lock() (unlock func(), err error) {
+	lockPath := l.path + ".lock"
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > l.ttl {
+			log.Warn("removing stale signing lease lock", "path", lockPath)
+			os.Remove(lockPath)
+		}
+		return nil, nil
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(lockPath)
+		return nil, err
+	}
+	return func() { os.Remove(lockPath) }, nil
+}
+
+func (l *fileLease) read() (*leaseRecord, error) {
+	data, err := ioutil.ReadFile(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var rec leaseRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("corrupted lease file: %v", err)
+	}
+	return &rec, nil
+}
+
+func (l *fileLease) write(rec *leaseRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(l.path), filepath.Base(l.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	// rename is atomic, so the file is never seen partially written
+	return os.Rename(tmp.Name(), l.path)
+}
+
+type heartbeatLease struct {
+	client   *http.Client
+	url      string
+	ttl      time.Duration
+	lastSeen time.Time
+	others   uint32
+}
+
+// NewHeartbeatLease create a lease for a standby node, which watches the primary node via its API.
+// The lease is held once the primary doesn't hold its lease for ttl, and released once the primary is back.
+// The primary keeps signing while unreachable, so both nodes must sign via the same remote signer,
+// whose guard refuses to sign a different block at a height already signed.
+func NewHeartbeatLease(primaryURL string, ttl time.Duration) Lease {
+	return &heartbeatLease{
+		client:   &http.Client{Timeout: time.Second},
+		url:      strings.TrimRight(primaryURL, "/") + "/node/lease",
+		ttl:      ttl,
+		lastSeen: time.Now(),
+	}
+}
+
+func (l *heartbeatLease) Renew(uint32) (time.Time, uint32, error) {
+	var status struct {
+		Held   bool   `json:"held"`
+		Signed uint32 `json:"signed"`
+	}
+	err := func() error {
+		res, err := l.client.Get(l.url)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			return fmt.Errorf("primary: %v", res.Status)
+		}
+		return json.NewDecoder(res.Body).Decode(&status)
+	}()
+	if err == nil {
+		if status.Signed > l.others {
+			l.others = status.Signed
+		}
+		if status.Held {
+			l.lastSeen = time.Now()
+			return time.Time{}, l.others, nil
+		}
+	} else {
+		log.Debug("primary heartbeat failed", "err", err)
+	}
+	if time.Since(l.lastSeen) <= l.ttl {
+		return time.Time{}, l.others, nil
+	}
+	// held until the next check finds the primary back
+	return time.Now().Add(l.ttl), l.others, nil
+}
+
+// Reserve does nothing, as the primary can't be reached reliably. Heights are guarded by the shared remote signer.
+func (l *heartbeatLease) Reserve(uint32) error {
+	return nil
+}
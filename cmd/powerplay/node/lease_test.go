@@ -0,0 +1,160 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package node
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func held(expires time.Time, others uint32, err error) (bool, uint32, error) {
+	return time.Now().Before(expires), others, err
+}
+
+func TestFileLease(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lease")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "lease")
+	ttl := 200 * time.Millisecond
+	primary := NewFileLease(path, ttl)
+	standby := NewFileLease(path, ttl)
+
+	ok, _, err := held(primary.Renew(10))
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, others, err := held(standby.Renew(0))
+	assert.Nil(t, err)
+	assert.False(t, ok, "held by primary")
+	assert.Equal(t, uint32(10), others)
+
+	ok, _, _ = held(primary.Renew(11))
+	assert.True(t, ok, "renewed")
+
+	// primary stops renewing
+	time.Sleep(ttl + 50*time.Millisecond)
+	ok, others, err = held(standby.Renew(0))
+	assert.Nil(t, err)
+	assert.True(t, ok, "taken over")
+	assert.Equal(t, uint32(11), others)
+
+	ok, _, _ = held(primary.Renew(11))
+	assert.False(t, ok, "held by standby")
+}
+
+func TestFileLeaseReserve(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lease")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "lease")
+	ttl := 200 * time.Millisecond
+	primary := NewFileLease(path, ttl)
+	standby := NewFileLease(path, ttl)
+
+	ok, _, _ := held(primary.Renew(10))
+	assert.True(t, ok)
+	assert.NotNil(t, standby.Reserve(11), "not held")
+
+	// primary reserves #11, signs it, and crashes before renewing
+	assert.Nil(t, primary.Reserve(11))
+
+	time.Sleep(ttl + 50*time.Millisecond)
+	ok, others, err := held(standby.Renew(0))
+	assert.Nil(t, err)
+	assert.True(t, ok, "taken over")
+	assert.Equal(t, uint32(11), others, "reserved height not to be signed again")
+
+	// reserved height is never lowered by renewal
+	assert.Nil(t, standby.Reserve(13))
+	_, _, err = standby.Renew(12)
+	assert.Nil(t, err)
+	ok, others, _ = held(primary.Renew(0))
+	assert.False(t, ok)
+	assert.Equal(t, uint32(13), others)
+}
+
+func TestFileLeaseLocked(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lease")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "lease")
+	ttl := 200 * time.Millisecond
+	lease := NewFileLease(path, ttl)
+
+	expires, _, err := lease.Renew(0)
+	assert.Nil(t, err)
+
+	// another one is acquiring
+	assert.Nil(t, ioutil.WriteFile(path+".lock", nil, 0600))
+	kept, _, err := lease.Renew(0)
+	assert.Nil(t, err)
+	assert.Equal(t, expires, kept, "kept until expiry, but not extended")
+
+	// stale lock removed
+	time.Sleep(ttl + 50*time.Millisecond)
+	ok, _, _ := held(lease.Renew(0))
+	assert.False(t, ok)
+	ok, _, err = held(lease.Renew(0))
+	assert.Nil(t, err)
+	assert.True(t, ok)
+}
+
+func TestHoldsLease(t *testing.T) {
+	n := &Node{lease: NewHeartbeatLease("http://localhost", time.Second)}
+	assert.False(t, n.holdsLease())
+
+	atomic.StoreInt64(&n.leaseExpires, time.Now().Add(time.Hour).UnixNano())
+	assert.True(t, n.holdsLease())
+
+	atomic.StoreInt64(&n.leaseExpires, time.Now().Add(-time.Millisecond).UnixNano())
+	assert.False(t, n.holdsLease(), "expired by local time")
+
+	assert.True(t, (&Node{}).holdsLease(), "no lease configured")
+}
+
+func TestHeartbeatLease(t *testing.T) {
+	primaryHeld := int32(1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if atomic.LoadInt32(&primaryHeld) != 0 {
+			w.Write([]byte(`{"held":true,"signed":10}`))
+		} else {
+			w.Write([]byte(`{"held":false,"signed":10}`))
+		}
+	}))
+	defer srv.Close()
+
+	ttl := 200 * time.Millisecond
+	standby := NewHeartbeatLease(srv.URL, ttl)
+
+	ok, others, err := held(standby.Renew(0))
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, uint32(10), others)
+
+	atomic.StoreInt32(&primaryHeld, 0)
+	ok, _, _ = held(standby.Renew(0))
+	assert.False(t, ok, "within ttl")
+
+	time.Sleep(ttl + 50*time.Millisecond)
+	ok, _, _ = held(standby.Renew(0))
+	assert.True(t, ok, "primary stopped")
+
+	atomic.StoreInt32(&primaryHeld, 1)
+	ok, _, _ = held(standby.Renew(0))
+	assert.False(t, ok, "primary back")
+}
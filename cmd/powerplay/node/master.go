@@ -7,17 +7,15 @@
 package node
 
 import (
-	"crypto/ecdsa"
-
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/signer"
 )
 
 type Master struct {
-	PrivateKey  *ecdsa.PrivateKey
+	Signer      signer.Signer
 	Beneficiary *powerplay.Address
 }
 
 func (m *Master) Address() powerplay.Address {
-	return powerplay.Address(crypto.PubkeyToAddress(m.PrivateKey.PublicKey))
+	return m.Signer.Address()
 }
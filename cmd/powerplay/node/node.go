@@ -19,15 +19,19 @@ import (
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/inconshreveable/log15"
 	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/bft"
 	"github.com/playmakerchain/powerplay/block"
 	"github.com/playmakerchain/powerplay/cache"
 	"github.com/playmakerchain/powerplay/chain"
 	"github.com/playmakerchain/powerplay/co"
 	"github.com/playmakerchain/powerplay/comm"
 	"github.com/playmakerchain/powerplay/consensus"
+	"github.com/playmakerchain/powerplay/equivocation"
 	"github.com/playmakerchain/powerplay/logdb"
 	"github.com/playmakerchain/powerplay/lvldb"
 	"github.com/playmakerchain/powerplay/packer"
+	"github.com/playmakerchain/powerplay/poa"
+	"github.com/playmakerchain/powerplay/proposers"
 	"github.com/playmakerchain/powerplay/state"
 	"github.com/playmakerchain/powerplay/powerplay"
 	"github.com/playmakerchain/powerplay/tx"
@@ -40,15 +44,38 @@ type Node struct {
 	goes   co.Goes
 	packer *packer.Packer
 	cons   *consensus.Consensus
+	bft    *bft.Engine
 
 	master         *Master
 	chain          *chain.Chain
+	stateCreator   *state.Creator
 	logDB          *logdb.LogDB
+	history        *state.History
+	proposerIndex  *proposers.Index
 	txPool         *txpool.TxPool
 	txStashPath    string
 	comm           *comm.Communicator
+	detector       *equivocation.Detector
+	evidencePool   *equivocation.Pool
 	commitLock     sync.Mutex
 	targetGasLimit uint64
+	gasLimitCtl    *packer.GasLimitController // nil if target gas limit is fixed
+
+	lease        Lease  // nil if not standby
+	leaseExpires int64  // atomic, unix time in nanoseconds when the held lease expires
+	signFloor    uint32 // atomic, the highest block number signed by other lease holders
+	lastSigned   uint32 // atomic, the highest block number signed by this node
+}
+
+// Options optional components and settings of Node.
+type Options struct {
+	History        *state.History   // nil if not in archive mode
+	ProposerIndex  *proposers.Index // nil if not enabled
+	EvidencePool   *equivocation.Pool
+	TargetGasLimit uint64
+	GasLimitCtl    *packer.GasLimitController // nil if target gas limit is fixed
+	TxStrategy     packer.Strategy            // nil to keep pool order
+	Lease          Lease                      // nil if not standby
 }
 
 func New(
@@ -59,19 +86,35 @@ func New(
 	txPool *txpool.TxPool,
 	txStashPath string,
 	comm *comm.Communicator,
-	targetGasLimit uint64,
+	opts Options,
 ) *Node {
-	return &Node{
+	n := &Node{
 		packer:         packer.New(chain, stateCreator, master.Address(), master.Beneficiary),
 		cons:           consensus.New(chain, stateCreator),
+		bft:            bft.New(chain, stateCreator),
 		master:         master,
 		chain:          chain,
+		stateCreator:   stateCreator,
 		logDB:          logDB,
+		history:        opts.History,
+		proposerIndex:  opts.ProposerIndex,
 		txPool:         txPool,
 		txStashPath:    txStashPath,
 		comm:           comm,
-		targetGasLimit: targetGasLimit,
+		detector:       equivocation.NewDetector(),
+		evidencePool:   opts.EvidencePool,
+		targetGasLimit: opts.TargetGasLimit,
+		gasLimitCtl:    opts.GasLimitCtl,
+		lease:          opts.Lease,
+	}
+	n.master = &Master{
+		Signer:      &leaseSigner{master.Signer, n},
+		Beneficiary: master.Beneficiary,
 	}
+	if opts.TxStrategy != nil {
+		n.packer.SetStrategy(opts.TxStrategy)
+	}
+	return n
 }
 
 func (n *Node) Run(ctx context.Context) error {
@@ -79,7 +122,17 @@ func (n *Node) Run(ctx context.Context) error {
 
 	n.goes.Go(func() { n.houseKeeping(ctx) })
 	n.goes.Go(func() { n.txStashLoop(ctx) })
+	if n.lease != nil {
+		n.goes.Go(func() { n.leaseLoop(ctx) })
+	}
 	n.goes.Go(func() { n.packerLoop(ctx) })
+	n.goes.Go(func() { n.finalityLoop(ctx) })
+	if n.history != nil {
+		n.goes.Go(func() { n.historyLoop(ctx) })
+	}
+	if n.proposerIndex != nil {
+		n.goes.Go(func() { n.proposerLoop(ctx) })
+	}
 
 	n.goes.Wait()
 	return nil
@@ -130,8 +183,11 @@ func (n *Node) houseKeeping(ctx context.Context) {
 	newBlockCh := make(chan *comm.NewBlockEvent)
 	scope.Track(n.comm.SubscribeBlock(newBlockCh))
 
-	futureTicker := time.NewTicker(time.Duration(powerplay.BlockInterval) * time.Second)
-	defer futureTicker.Stop()
+	evidenceCh := make(chan *comm.NewEvidenceEvent)
+	scope.Track(n.comm.SubscribeEvidence(evidenceCh))
+
+	futureTimer := time.NewTimer(time.Duration(n.blockInterval()) * time.Second)
+	defer futureTimer.Stop()
 
 	connectivityTicker := time.NewTicker(time.Second)
 	defer connectivityTicker.Stop()
@@ -145,6 +201,8 @@ func (n *Node) houseKeeping(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case newBlock := <-newBlockCh:
+			n.checkEquivocation(newBlock.Header())
+
 			var stats blockStats
 			if isTrunk, err := n.processBlock(newBlock.Block, &stats); err != nil {
 				if consensus.IsFutureBlock(err) ||
@@ -156,7 +214,12 @@ func (n *Node) houseKeeping(ctx context.Context) {
 				n.comm.BroadcastBlock(newBlock.Block)
 				log.Info(fmt.Sprintf("imported blocks (%v)", stats.processed), stats.LogContext(newBlock.Block.Header())...)
 			}
-		case <-futureTicker.C:
+		case ev := <-evidenceCh:
+			if err := n.reportEvidence(ev.Evidence); err != nil {
+				log.Debug("evidence rejected", "evidence", ev.Evidence, "err", err)
+			}
+		case <-futureTimer.C:
+			futureTimer.Reset(time.Duration(n.blockInterval()) * time.Second)
 			// process future blocks
 			var blocks []*block.Block
 			futureBlocks.ForEach(func(ent *cache.Entry) bool {
@@ -185,7 +248,7 @@ func (n *Node) houseKeeping(ctx context.Context) {
 				noPeerTimes++
 				if noPeerTimes > 30 {
 					noPeerTimes = 0
-					go checkClockOffset()
+					go checkClockOffset(n.blockInterval())
 				}
 			} else {
 				noPeerTimes = 0
@@ -266,7 +329,11 @@ func (n *Node) processBlock(blk *block.Block, stats *blockStats) (bool, error) {
 
 	fork, err := n.commitBlock(blk, receipts)
 	if err != nil {
-		if !n.chain.IsBlockExist(err) {
+		switch {
+		case n.chain.IsBlockExist(err):
+		case n.chain.IsFinalizedConflict(err):
+			log.Warn("refused block conflicts with finalized block", "id", blk.Header().ID(), "finalized", n.chain.FinalizedBlock().Number())
+		default:
 			log.Error("failed to commit block", "err", err)
 		}
 		return false, err
@@ -332,13 +399,48 @@ branch:   %v  %v`, fork.Ancestor,
 	}
 }
 
-func checkClockOffset() {
+// Rewind resets the best block to the trunk block with given number.
+// Logs above are dropped, and txs of removed blocks are returned to tx pool.
+func (n *Node) Rewind(num uint32) error {
+	n.commitLock.Lock()
+	defer n.commitLock.Unlock()
+
+	removed, err := n.chain.Rewind(num)
+	if err != nil {
+		return err
+	}
+	if err := n.logDB.Truncate(num); err != nil {
+		return errors.Wrap(err, "truncate logs")
+	}
+	for _, b := range removed {
+		for _, tx := range b.Transactions() {
+			if err := n.txPool.Add(tx); err != nil {
+				log.Debug("failed to add tx to tx pool", "err", err, "id", tx.ID())
+			}
+		}
+	}
+	log.Warn("chain rewound", "removed", len(removed), "id", shortID(n.chain.BestBlock().Header().ID()))
+	return nil
+}
+
+// blockInterval returns the block interval in effect for the block after best block.
+func (n *Node) blockInterval() uint64 {
+	forkConfig := powerplay.GetForkConfig(n.chain.GenesisBlock().Header().ID())
+	params, err := poa.LoadParamsAfter(n.stateCreator, forkConfig, n.chain.BestBlock().Header())
+	if err != nil {
+		log.Warn("failed to load block interval", "err", err)
+		return poa.DefaultParams.BlockInterval
+	}
+	return params.BlockInterval
+}
+
+func checkClockOffset(blockInterval uint64) {
 	resp, err := ntp.Query("ap.pool.ntp.org")
 	if err != nil {
 		log.Debug("failed to access NTP", "err", err)
 		return
 	}
-	if resp.ClockOffset > time.Duration(powerplay.BlockInterval)*time.Second/2 {
+	if resp.ClockOffset > time.Duration(blockInterval)*time.Second/2 {
 		log.Warn("clock offset detected", "offset", common.PrettyDuration(resp.ClockOffset))
 	}
 }
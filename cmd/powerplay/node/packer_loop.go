@@ -9,14 +9,15 @@ package node
 import (
 	"context"
 	"fmt"
-	"math"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/mclock"
 	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/block"
 	"github.com/playmakerchain/powerplay/packer"
 	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/signer"
 )
 
 func (n *Node) packerLoop(ctx context.Context) {
@@ -48,6 +49,12 @@ func (n *Node) packerLoop(ctx context.Context) {
 		case <-ticker.C:
 		}
 
+		if !n.holdsLease() {
+			// standing by
+			flow = nil
+			continue
+		}
+
 		best := n.chain.BestBlock()
 		now := uint64(time.Now().Unix())
 
@@ -83,7 +90,7 @@ func (n *Node) packerLoop(ctx context.Context) {
 }
 
 func (n *Node) pack(flow *packer.Flow) error {
-	txs := n.txPool.Executables()
+	txs := n.pendingTxs()
 	var txsToRemove []powerplay.Bytes32
 	defer func() {
 		for _, id := range txsToRemove {
@@ -92,23 +99,16 @@ func (n *Node) pack(flow *packer.Flow) error {
 	}()
 
 	startTime := mclock.Now()
-	for _, tx := range txs {
-		if err := flow.Adopt(tx); err != nil {
-			if packer.IsGasLimitReached(err) {
-				break
-			}
-			if packer.IsTxNotAdoptableNow(err) {
-				continue
-			}
-			txsToRemove = append(txsToRemove, tx.ID())
-		}
-	}
+	txsToRemove = flow.AdoptPending(txs, powerplay.TolerableBlockPackingTime)
 
-	newBlock, stage, receipts, err := flow.Pack(n.master.PrivateKey)
+	// time spent on signing, which may be remote, is not taken as execution time
+	timer := &signTimer{Signer: n.master.Signer}
+	newBlock, stage, receipts, err := flow.PackWithSigner(timer)
 	if err != nil {
 		return err
 	}
-	execElapsed := mclock.Now() - startTime
+	commitStart := mclock.Now()
+	execElapsed := commitStart - startTime - timer.elapsed
 
 	if _, err := stage.Commit(); err != nil {
 		return errors.WithMessage(err, "commit state")
@@ -118,7 +118,7 @@ func (n *Node) pack(flow *packer.Flow) error {
 	if err != nil {
 		return errors.WithMessage(err, "commit block")
 	}
-	commitElapsed := mclock.Now() - startTime - execElapsed
+	commitElapsed := mclock.Now() - commitStart
 
 	n.processFork(fork)
 
@@ -132,17 +132,84 @@ func (n *Node) pack(flow *packer.Flow) error {
 		)
 	}
 
-	if n.targetGasLimit == 0 {
-		n.packer.SetTargetGasLimit(0)
-		if execElapsed > 0 {
-			gasUsed := newBlock.Header().GasUsed()
-			// calc target gas limit only if gas used above third of gas limit
-			if gasUsed > newBlock.Header().GasLimit()/3 {
-				targetGasLimit := uint64(math.Log2(float64(newBlock.Header().Number()+1))*float64(powerplay.TolerableBlockPackingTime)*float64(gasUsed)) / (32 * uint64(execElapsed))
-				n.packer.SetTargetGasLimit(targetGasLimit)
-				log.Debug("reset target gas limit", "value", targetGasLimit)
-			}
-		}
+	if n.gasLimitCtl != nil {
+		backlog := len(txs) - len(receipts) - len(txsToRemove)
+		targetGasLimit := n.gasLimitCtl.Update(newBlock.Header().GasUsed(), newBlock.Header().GasLimit(), time.Duration(execElapsed), backlog)
+		n.packer.SetTargetGasLimit(targetGasLimit)
+		log.Debug("reset target gas limit", "value", targetGasLimit, "backlog", backlog)
 	}
 	return nil
 }
+
+// signTimer measures time spent on signing blocks.
+type signTimer struct {
+	signer.Signer
+	elapsed mclock.AbsTime
+}
+
+func (s *signTimer) SignBlock(header *block.Header) ([]byte, error) {
+	start := mclock.Now()
+	defer func() { s.elapsed += mclock.Now() - start }()
+	return s.Signer.SignBlock(header)
+}
+
+// pendingTxs returns executable txs in pool, along with their metadata for the packer strategy.
+func (n *Node) pendingTxs() []*packer.PendingTx {
+	txs := n.txPool.Executables()
+	pending := make([]*packer.PendingTx, 0, len(txs))
+	for _, tx := range txs {
+		origin, err := tx.Signer()
+		if err != nil {
+			continue
+		}
+		timeAdded, ok := n.txPool.TimeAdded(tx.ID())
+		if !ok {
+			// removed meanwhile
+			continue
+		}
+		pending = append(pending, &packer.PendingTx{
+			Transaction: tx,
+			Origin:      origin,
+			TimeAdded:   timeAdded,
+		})
+	}
+	return pending
+}
+
+// GasLimitStatus returns state of the target gas limit controller. The fixed target is returned
+// if not adaptive.
+func (n *Node) GasLimitStatus() (adaptive bool, status packer.GasLimitStatus) {
+	if n.gasLimitCtl == nil {
+		return false, packer.GasLimitStatus{Target: n.targetGasLimit}
+	}
+	return true, n.gasLimitCtl.Status()
+}
+
+// ProposerStatus reports status of the node master upon the best block, and time of up to `slots`
+// next slots. If dryRun, a block is built with pending txs, without being signed or broadcast.
+func (n *Node) ProposerStatus(slots int, dryRun bool) (*packer.ProposerStatus, *packer.DryRunResult, error) {
+	best := n.chain.BestBlock().Header()
+	now := uint64(time.Now().Unix())
+
+	status, err := n.packer.Status(best, now, slots)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !dryRun {
+		return status, nil, nil
+	}
+
+	targetTime := now
+	if len(status.Slots) > 0 {
+		targetTime = status.Slots[0]
+	}
+	gasLimit := best.GasLimit()
+	if _, gl := n.GasLimitStatus(); gl.Target != 0 {
+		gasLimit = block.GasLimit(gl.Target).Qualify(gasLimit)
+	}
+	result, err := n.packer.DryRun(best, targetTime, gasLimit, n.pendingTxs())
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "dry run")
+	}
+	return status, result, nil
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package node
+
+import (
+	"context"
+)
+
+// proposerLoop keeps proposer index in line with trunk.
+func (n *Node) proposerLoop(ctx context.Context) {
+	log.Debug("enter proposer loop")
+	defer log.Debug("leave proposer loop")
+
+	ticker := n.chain.NewTicker()
+	for {
+		if err := n.proposerIndex.Sync(ctx); err != nil {
+			log.Warn("failed to index proposers", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
+	}
+}
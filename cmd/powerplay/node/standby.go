@@ -0,0 +1,113 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/bft"
+	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/signer"
+)
+
+var errLeaseNotHeld = errors.New("signing lease not held")
+
+// leaseSigner guards the master signer, so that it signs only when the lease is held, and never
+// signs at a height already signed by other lease holders.
+type leaseSigner struct {
+	signer.Signer
+	node *Node
+}
+
+func (s *leaseSigner) SignBlock(header *block.Header) ([]byte, error) {
+	if !s.node.holdsLease() {
+		return nil, errLeaseNotHeld
+	}
+	if floor := atomic.LoadUint32(&s.node.signFloor); header.Number() <= floor {
+		return nil, fmt.Errorf("refuse to sign block #%v: other lease holder signed up to #%v", header.Number(), floor)
+	}
+	if s.node.lease != nil {
+		// write-ahead, so that others taking over know the height even if the node stops right after signing
+		if err := s.node.lease.Reserve(header.Number()); err != nil {
+			return nil, errors.WithMessage(err, "reserve block number to sign")
+		}
+	}
+	sig, err := s.Signer.SignBlock(header)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		signed := atomic.LoadUint32(&s.node.lastSigned)
+		if header.Number() <= signed || atomic.CompareAndSwapUint32(&s.node.lastSigned, signed, header.Number()) {
+			break
+		}
+	}
+	return sig, nil
+}
+
+func (s *leaseSigner) SignVote(vote *bft.Vote) ([]byte, error) {
+	if !s.node.holdsLease() {
+		return nil, errLeaseNotHeld
+	}
+	return s.Signer.SignVote(vote)
+}
+
+// holdsLease returns whether the node may sign. It's always true without lease configured.
+// The expiry is checked against local time, so the lease is dropped in time even if renewal stalls.
+func (n *Node) holdsLease() bool {
+	return n.lease == nil || time.Now().UnixNano() < atomic.LoadInt64(&n.leaseExpires)
+}
+
+// LeaseStatus returns whether the node holds signing lease, and the highest block number it signed.
+func (n *Node) LeaseStatus() (held bool, signed uint32) {
+	return n.holdsLease(), atomic.LoadUint32(&n.lastSigned)
+}
+
+// leaseLoop keeps renewing the lease, which decides whether the node packs blocks.
+func (n *Node) leaseLoop(ctx context.Context) {
+	log.Debug("enter lease loop")
+	defer log.Debug("leave lease loop")
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	log.Info("standing by, waiting for signing lease...")
+	var wasHeld bool
+	for {
+		expires, othersSigned, err := n.lease.Renew(atomic.LoadUint32(&n.lastSigned))
+		if err != nil {
+			// fail safe
+			expires = time.Time{}
+			log.Warn("failed to renew signing lease", "err", err)
+		}
+		// raise the floor before the lease takes effect
+		atomic.StoreUint32(&n.signFloor, othersSigned)
+
+		var expiresNano int64
+		if !expires.IsZero() {
+			expiresNano = expires.UnixNano()
+		}
+		atomic.StoreInt64(&n.leaseExpires, expiresNano)
+
+		held := n.holdsLease()
+		if held && !wasHeld {
+			log.Info("signing lease acquired, start packing", "signed by others", othersSigned)
+		} else if !held && wasHeld {
+			log.Warn("signing lease lost, stop packing")
+		}
+		wasHeld = held
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
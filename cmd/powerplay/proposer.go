@@ -0,0 +1,49 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/playmakerchain/powerplay/api/node"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+func proposerStatusAction(ctx *cli.Context) error {
+	initLogger(ctx)
+
+	path := fmt.Sprintf("/node/proposer?slots=%d&dryRun=%v", ctx.Int(proposerSlotsFlag.Name), ctx.Bool(dryRunFlag.Name))
+	var status node.ProposerStatus
+	if err := callAPI(ctx, "GET", path, nil, &status); err != nil {
+		return err
+	}
+
+	fmt.Println("master:     ", status.Master)
+	fmt.Println("listed:     ", status.Listed)
+	if !status.Listed {
+		return nil
+	}
+	fmt.Println("active:     ", status.Active)
+	fmt.Println("endorsor:   ", status.Endorsor)
+	fmt.Printf("balance:     %v (endorsement %v)\n", (*big.Int)(status.EndorsorBalance), (*big.Int)(status.Endorsement))
+	fmt.Println("candidate:  ", status.Candidate)
+	for i, slot := range status.Slots {
+		fmt.Printf("slot #%d:     %v\n", i+1, time.Unix(int64(slot), 0))
+	}
+
+	if r := status.DryRun; r != nil {
+		fmt.Println("dry run:")
+		fmt.Println("  number:   ", r.Number)
+		fmt.Println("  time:     ", time.Unix(int64(r.Timestamp), 0))
+		fmt.Printf("  gas:       %v/%v\n", r.GasUsed, r.GasLimit)
+		fmt.Println("  txs:      ", r.TxCount)
+		fmt.Println("  stateRoot:", r.StateRoot)
+		fmt.Println("  elapsed:  ", time.Duration(r.Elapsed)*time.Millisecond)
+	}
+	return nil
+}
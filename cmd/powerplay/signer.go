@@ -0,0 +1,107 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/co"
+	"github.com/playmakerchain/powerplay/lvldb"
+	"github.com/playmakerchain/powerplay/signer"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+func signerAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+	initLogger(ctx)
+
+	configDir := makeConfigDir(ctx)
+	key, err := loadSigningKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	// signed records are kept by the signer itself, to protect the key even if nodes lose their data
+	dbPath := filepath.Join(configDir, "signer.db")
+	db, err := lvldb.New(dbPath, lvldb.Options{})
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("open signer database [%v]", dbPath))
+	}
+	defer db.Close()
+
+	endpoint := ctx.String(signerListenFlag.Name)
+	if endpoint == "" {
+		endpoint = "unix://" + filepath.Join(configDir, "signer.sock")
+	}
+	network, address, err := signer.ParseEndpoint(endpoint)
+	if err != nil {
+		return err
+	}
+	token, err := readSignerToken(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	if network == "unix" {
+		// remove the socket file left by previous run
+		if err := os.Remove(address); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("listen [%v]", endpoint))
+	}
+	if network == "unix" {
+		if err := os.Chmod(address, 0600); err != nil {
+			listener.Close()
+			return err
+		}
+	}
+
+	guard := signer.NewGuard(signer.NewKeySigner(key), db)
+	srv := &http.Server{Handler: signer.NewServer(guard, token).Handler()}
+	var goes co.Goes
+	goes.Go(func() {
+		srv.Serve(listener)
+	})
+	defer func() { srv.Close(); goes.Wait() }()
+
+	log.Info("signer started", "address", guard.Address(), "endpoint", endpoint)
+	<-exitSignal.Done()
+	return nil
+}
+
+// readSignerToken reads the token shared by the signer and nodes.
+// It's required for TCP endpoints, which can be reached by others, unlike the unix socket only accessible by the owner.
+func readSignerToken(ctx *cli.Context, endpoint string) (string, error) {
+	network, _, err := signer.ParseEndpoint(endpoint)
+	if err != nil {
+		return "", err
+	}
+	path := ctx.String(signerTokenFileFlag.Name)
+	if path == "" {
+		if network != "unix" {
+			return "", fmt.Errorf("signer endpoint %v requires flag %v", endpoint, signerTokenFileFlag.Name)
+		}
+		return "", nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", errors.WithMessage(err, "read signer token")
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", errors.New("empty signer token")
+	}
+	return token, nil
+}
@@ -0,0 +1,284 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/api/transactions"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/tx"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+// encodeRawTx encodes tx into hex encoded rlp.
+func encodeRawTx(trx *tx.Transaction) (string, error) {
+	data, err := rlp.EncodeToBytes(trx)
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(data), nil
+}
+
+// decodeRawTx decodes tx from hex encoded rlp.
+func decodeRawTx(raw string) (*tx.Transaction, error) {
+	data, err := hexutil.Decode(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, errors.WithMessage(err, "raw tx")
+	}
+	var trx tx.Transaction
+	if err := rlp.DecodeBytes(data, &trx); err != nil {
+		return nil, errors.WithMessage(err, "raw tx")
+	}
+	return &trx, nil
+}
+
+func txWorkAction(ctx *cli.Context) error {
+	initLogger(ctx)
+
+	raw, err := readArg(ctx, "hex encoded rlp of the unsigned tx")
+	if err != nil {
+		return err
+	}
+	trx, err := decodeRawTx(raw)
+	if err != nil {
+		return err
+	}
+	origin, err := powerplay.ParseAddress(ctx.String(txOriginFlag.Name))
+	if err != nil {
+		return errors.WithMessage(err, "origin")
+	}
+
+	var target *big.Int
+	switch {
+	case ctx.IsSet(txWorkFlag.Name) && ctx.IsSet(txWorkGasFlag.Name):
+		return fmt.Errorf("flag %s and %s are exclusive", txWorkFlag.Name, txWorkGasFlag.Name)
+	case ctx.IsSet(txWorkFlag.Name):
+		var ok bool
+		if target, ok = new(big.Int).SetString(ctx.String(txWorkFlag.Name), 10); !ok {
+			return errors.New("invalid work")
+		}
+	case ctx.IsSet(txWorkGasFlag.Name):
+		gas := ctx.Uint64(txWorkGasFlag.Name)
+		if gas > trx.Gas() {
+			return errors.New("work gas exceeds tx gas")
+		}
+		target = tx.GasToWork(gas, trx.BlockRef().Number())
+	default:
+		return fmt.Errorf("missing flag, either %s or %s", txWorkFlag.Name, txWorkGasFlag.Name)
+	}
+
+	startTime := time.Now()
+	nonce, work, err := tx.SearchWork(handleExitSignal(), trx, origin, target, ctx.Int(txThreadsFlag.Name))
+	if err != nil {
+		return errors.WithMessage(err, "search work")
+	}
+	fmt.Println("nonce:  ", nonce)
+	fmt.Println("work:   ", work)
+	fmt.Println("elapsed:", time.Since(startTime))
+	return nil
+}
+
+// callAPI sends request to the node's REST API, and decodes JSON response into result.
+func callAPI(ctx *cli.Context, method, path string, body interface{}, result interface{}) error {
+	var reqBody []byte
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = data
+	}
+	req, err := http.NewRequest(method, strings.TrimRight(ctx.String(txAPIURLFlag.Name), "/")+path, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%v %v: %v %v", method, path, res.Status, strings.TrimSpace(string(data)))
+	}
+	return json.Unmarshal(data, result)
+}
+
+// readArg returns the only argument, or reads it from stdin if the argument is "-".
+func readArg(ctx *cli.Context, name string) (string, error) {
+	if ctx.NArg() != 1 {
+		return "", fmt.Errorf("requires exactly one argument: %v", name)
+	}
+	arg := ctx.Args().First()
+	if arg == "-" {
+		data, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return "", err
+		}
+		arg = string(data)
+	}
+	return strings.TrimSpace(arg), nil
+}
+
+func txBuildAction(ctx *cli.Context) error {
+	initLogger(ctx)
+
+	clausesJSON, err := readArg(ctx, "JSON array of clauses")
+	if err != nil {
+		return err
+	}
+	var clauses transactions.Clauses
+	if err := json.Unmarshal([]byte(clausesJSON), &clauses); err != nil {
+		return errors.WithMessage(err, "clauses")
+	}
+
+	if ctx.Uint64(txGasPriceCoefFlag.Name) > math.MaxUint8 {
+		return errors.New("gas price coef out of range")
+	}
+
+	var genesis, best struct {
+		ID powerplay.Bytes32 `json:"id"`
+	}
+	if err := callAPI(ctx, "GET", "/blocks/0", nil, &genesis); err != nil {
+		return errors.WithMessage(err, "get genesis block")
+	}
+	if err := callAPI(ctx, "GET", "/blocks/best", nil, &best); err != nil {
+		return errors.WithMessage(err, "get best block")
+	}
+
+	builder := new(tx.Builder).
+		ChainTag(genesis.ID[31]).
+		BlockRef(tx.NewBlockRefFromID(best.ID)).
+		Expiration(uint32(ctx.Uint64(txExpirationFlag.Name))).
+		GasPriceCoef(uint8(ctx.Uint64(txGasPriceCoefFlag.Name))).
+		Nonce(rand.Uint64())
+	if ctx.IsSet(txNonceFlag.Name) {
+		builder.Nonce(ctx.Uint64(txNonceFlag.Name))
+	}
+	if dep := ctx.String(txDependsOnFlag.Name); dep != "" {
+		id, err := powerplay.ParseBytes32(dep)
+		if err != nil {
+			return errors.WithMessage(err, "depends on")
+		}
+		builder.DependsOn(&id)
+	}
+	for i, c := range clauses {
+		data, err := hexutil.Decode(c.Data)
+		if err != nil && c.Data != "" {
+			return errors.WithMessage(err, fmt.Sprintf("clause #%v data", i))
+		}
+		value := big.Int(c.Value)
+		builder.Clause(tx.NewClause(c.To).WithValue(&value).WithData(data))
+	}
+
+	gas := ctx.Uint64(txGasFlag.Name)
+	if gas == 0 {
+		// without gas specified, intrinsic gas is used, which only fits plain transfers
+		intrinsicGas, err := builder.Build().IntrinsicGas()
+		if err != nil {
+			return err
+		}
+		gas = intrinsicGas
+	}
+	raw, err := encodeRawTx(builder.Gas(gas).Build())
+	if err != nil {
+		return err
+	}
+	fmt.Println(raw)
+	return nil
+}
+
+func txSignAction(ctx *cli.Context) error {
+	initLogger(ctx)
+
+	raw, err := readArg(ctx, "hex encoded rlp of the unsigned tx")
+	if err != nil {
+		return err
+	}
+	trx, err := decodeRawTx(raw)
+	if err != nil {
+		return err
+	}
+	if trx.Features().IsDelegated() {
+		return errors.New("signing delegated tx is not supported")
+	}
+	key, err := unlockAccount(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "unlock account")
+	}
+	if key == nil {
+		if key, err = crypto.LoadECDSA(masterKeyPath(ctx)); err != nil {
+			return errors.WithMessage(err, "load master key")
+		}
+	}
+	sig, err := crypto.Sign(trx.SigningHash().Bytes(), key)
+	if err != nil {
+		return err
+	}
+	if raw, err = encodeRawTx(trx.WithSignature(sig)); err != nil {
+		return err
+	}
+	fmt.Println(raw)
+	return nil
+}
+
+func txSendAction(ctx *cli.Context) error {
+	initLogger(ctx)
+
+	raw, err := readArg(ctx, "hex encoded rlp of the signed tx")
+	if err != nil {
+		return err
+	}
+	if _, err := decodeRawTx(raw); err != nil {
+		return err
+	}
+	var result struct {
+		ID powerplay.Bytes32 `json:"id"`
+	}
+	if err := callAPI(ctx, "POST", "/transactions", &transactions.RawTx{Raw: raw}, &result); err != nil {
+		return err
+	}
+	fmt.Println(result.ID)
+	return nil
+}
+
+func txDecodeAction(ctx *cli.Context) error {
+	raw, err := readArg(ctx, "hex encoded rlp of the tx")
+	if err != nil {
+		return err
+	}
+	trx, err := decodeRawTx(raw)
+	if err != nil {
+		return err
+	}
+	fmt.Println(trx)
+	if intrinsicGas, err := trx.IntrinsicGas(); err != nil {
+		fmt.Println("IntrinsicGas: N/A", err)
+	} else {
+		fmt.Println("IntrinsicGas:", intrinsicGas)
+	}
+	return nil
+}
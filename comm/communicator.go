@@ -17,12 +17,16 @@ import (
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/inconshreveable/log15"
+	"github.com/playmakerchain/powerplay/bft"
 	"github.com/playmakerchain/powerplay/block"
 	"github.com/playmakerchain/powerplay/chain"
 	"github.com/playmakerchain/powerplay/co"
 	"github.com/playmakerchain/powerplay/comm/proto"
+	"github.com/playmakerchain/powerplay/equivocation"
 	"github.com/playmakerchain/powerplay/p2psrv"
+	"github.com/playmakerchain/powerplay/poa"
 	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/state"
 	"github.com/playmakerchain/powerplay/tx"
 	"github.com/playmakerchain/powerplay/txpool"
 )
@@ -32,12 +36,16 @@ var log = log15.New("pkg", "comm")
 // Communicator communicates with remote p2p peers to exchange blocks and txs, etc.
 type Communicator struct {
 	chain          *chain.Chain
+	stateCreator   *state.Creator
+	forkConfig     powerplay.ForkConfig
 	txPool         *txpool.TxPool
 	ctx            context.Context
 	cancel         context.CancelFunc
 	peerSet        *PeerSet
 	syncedCh       chan struct{}
 	newBlockFeed   event.Feed
+	newVoteFeed    event.Feed
+	evidenceFeed   event.Feed
 	announcementCh chan *announcement
 	feedScope      event.SubscriptionScope
 	goes           co.Goes
@@ -45,10 +53,12 @@ type Communicator struct {
 }
 
 // New create a new Communicator instance.
-func New(chain *chain.Chain, txPool *txpool.TxPool) *Communicator {
+func New(chain *chain.Chain, stateCreator *state.Creator, txPool *txpool.TxPool) *Communicator {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Communicator{
 		chain:          chain,
+		stateCreator:   stateCreator,
+		forkConfig:     powerplay.GetForkConfig(chain.GenesisBlock().Header().ID()),
 		txPool:         txPool,
 		ctx:            ctx,
 		cancel:         cancel,
@@ -75,9 +85,9 @@ func (c *Communicator) Sync(handler HandleBlockStream) {
 		syncCount := 0
 
 		shouldSynced := func() bool {
-			bestBlockTime := c.chain.BestBlock().Header().Timestamp()
+			best := c.chain.BestBlock().Header()
 			now := uint64(time.Now().Unix())
-			if bestBlockTime+powerplay.BlockInterval >= now {
+			if best.Timestamp()+c.blockInterval(best) >= now {
 				return true
 			}
 			if syncCount > 2 {
@@ -128,19 +138,38 @@ func (c *Communicator) Sync(handler HandleBlockStream) {
 	})
 }
 
+// blockInterval returns the block interval in effect for the block after the parent block.
+func (c *Communicator) blockInterval(parent *block.Header) uint64 {
+	params, err := poa.LoadParamsAfter(c.stateCreator, c.forkConfig, parent)
+	if err != nil {
+		log.Warn("failed to load block interval", "err", err)
+		return poa.DefaultParams.BlockInterval
+	}
+	return params.BlockInterval
+}
+
 // Protocols returns all supported protocols.
+// Both the current and legacy versions are advertised, and the highest one in common is negotiated.
+// The legacy one goes last, so that peers of both versions are searched for via its topic.
 func (c *Communicator) Protocols() []*p2psrv.Protocol {
 	genesisID := c.chain.GenesisBlock().Header().ID()
-	return []*p2psrv.Protocol{
-		&p2psrv.Protocol{
+	newProtocol := func(version uint, length uint64) *p2psrv.Protocol {
+		return &p2psrv.Protocol{
 			Protocol: p2p.Protocol{
 				Name:    proto.Name,
-				Version: proto.Version,
-				Length:  proto.Length,
-				Run:     c.servePeer,
+				Version: version,
+				Length:  length,
+				Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+					return c.servePeer(p, rw, version)
+				},
 			},
-			DiscTopic: fmt.Sprintf("%v%v@%x", proto.Name, proto.Version, genesisID[24:]),
-		}}
+			DiscTopic: fmt.Sprintf("%v%v@%x", proto.Name, version, genesisID[24:]),
+		}
+	}
+	return []*p2psrv.Protocol{
+		newProtocol(proto.Version, proto.Length),
+		newProtocol(proto.Version1, proto.Version1Length),
+	}
 }
 
 // Start start the communicator.
@@ -161,8 +190,8 @@ type txsToSync struct {
 	synced bool
 }
 
-func (c *Communicator) servePeer(p *p2p.Peer, rw p2p.MsgReadWriter) error {
-	peer := newPeer(p, rw)
+func (c *Communicator) servePeer(p *p2p.Peer, rw p2p.MsgReadWriter, version uint) error {
+	peer := newPeer(p, rw, version)
 	c.goes.Go(func() {
 		c.runPeer(peer)
 	})
@@ -197,7 +226,7 @@ func (c *Communicator) runPeer(peer *Peer) {
 	if localClock < remoteClock {
 		diff = remoteClock - localClock
 	}
-	if diff > powerplay.BlockInterval*2 {
+	if diff > c.blockInterval(c.chain.BestBlock().Header())*2 {
 		peer.logger.Debug("failed to handshake", "err", "sys time diff too large")
 		return
 	}
@@ -260,6 +289,50 @@ func (c *Communicator) BroadcastBlock(blk *block.Block) {
 	}
 }
 
+// SubscribeVote subscribe the event that new finality vote received.
+func (c *Communicator) SubscribeVote(ch chan *NewVoteEvent) event.Subscription {
+	return c.feedScope.Track(c.newVoteFeed.Subscribe(ch))
+}
+
+// BroadcastVote broadcast a finality vote to remote peers.
+// Votes are small and latency sensitive, so they are sent to all peers with finality supported.
+func (c *Communicator) BroadcastVote(vote *bft.Vote) {
+	hash := vote.Hash()
+	peers := c.peerSet.Slice().Filter(func(p *Peer) bool {
+		return p.SupportsFinality() && !p.IsVoteKnown(hash)
+	})
+
+	for _, peer := range peers {
+		peer := peer
+		peer.MarkVote(hash)
+		c.goes.Go(func() {
+			if err := proto.NotifyNewVote(c.ctx, peer, vote); err != nil {
+				peer.logger.Debug("failed to broadcast new vote", "err", err)
+			}
+		})
+	}
+}
+
+// SubscribeEvidence subscribe the event that new equivocation evidence received.
+func (c *Communicator) SubscribeEvidence(ch chan *NewEvidenceEvent) event.Subscription {
+	return c.feedScope.Track(c.evidenceFeed.Subscribe(ch))
+}
+
+// BroadcastEvidence broadcast an equivocation evidence to all remote peers with finality supported.
+func (c *Communicator) BroadcastEvidence(ev *equivocation.Evidence) {
+	peers := c.peerSet.Slice().Filter(func(p *Peer) bool {
+		return p.SupportsFinality()
+	})
+	for _, peer := range peers {
+		peer := peer
+		c.goes.Go(func() {
+			if err := proto.NotifyNewEvidence(c.ctx, peer, ev); err != nil {
+				peer.logger.Debug("failed to broadcast new evidence", "err", err)
+			}
+		})
+	}
+}
+
 // PeerCount returns count of peers.
 func (c *Communicator) PeerCount() int {
 	return c.peerSet.Len()
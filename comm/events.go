@@ -9,7 +9,9 @@ package comm
 import (
 	"context"
 
+	"github.com/playmakerchain/powerplay/bft"
 	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/equivocation"
 )
 
 // NewBlockEvent event emitted when received block announcement.
@@ -17,5 +19,15 @@ type NewBlockEvent struct {
 	*block.Block
 }
 
+// NewVoteEvent event emitted when received finality vote.
+type NewVoteEvent struct {
+	*bft.Vote
+}
+
+// NewEvidenceEvent event emitted when received equivocation evidence.
+type NewEvidenceEvent struct {
+	*equivocation.Evidence
+}
+
 // HandleBlockStream to handle the stream of downloaded blocks in sync process.
 type HandleBlockStream func(ctx context.Context, stream <-chan *block.Block) error
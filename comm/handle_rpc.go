@@ -13,8 +13,10 @@ import (
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/bft"
 	"github.com/playmakerchain/powerplay/block"
 	"github.com/playmakerchain/powerplay/comm/proto"
+	"github.com/playmakerchain/powerplay/equivocation"
 	"github.com/playmakerchain/powerplay/metric"
 	"github.com/playmakerchain/powerplay/powerplay"
 	"github.com/playmakerchain/powerplay/tx"
@@ -73,6 +75,21 @@ func (c *Communicator) handleRPC(peer *Peer, msg *p2p.Msg, write func(interface{
 		peer.MarkTransaction(newTx.ID())
 		c.txPool.StrictlyAdd(newTx)
 		write(&struct{}{})
+	case proto.MsgNewVote:
+		var newVote *bft.Vote
+		if err := msg.Decode(&newVote); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+		peer.MarkVote(newVote.Hash())
+		c.newVoteFeed.Send(&NewVoteEvent{Vote: newVote})
+		write(&struct{}{})
+	case proto.MsgNewEvidence:
+		var newEvidence *equivocation.Evidence
+		if err := msg.Decode(&newEvidence); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+		c.evidenceFeed.Send(&NewEvidenceEvent{Evidence: newEvidence})
+		write(&struct{}{})
 	case proto.MsgGetBlockByID:
 		var blockID powerplay.Bytes32
 		if err := msg.Decode(&blockID); err != nil {
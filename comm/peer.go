@@ -16,6 +16,7 @@ import (
 	"github.com/ethereum/go-ethereum/p2p/discover"
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/inconshreveable/log15"
+	"github.com/playmakerchain/powerplay/comm/proto"
 	"github.com/playmakerchain/powerplay/p2psrv/rpc"
 	"github.com/playmakerchain/powerplay/powerplay"
 )
@@ -23,6 +24,7 @@ import (
 const (
 	maxKnownTxs    = 32768 // Maximum transactions IDs to keep in the known list (prevent DOS)
 	maxKnownBlocks = 1024  // Maximum block IDs to keep in the known list (prevent DOS)
+	maxKnownVotes  = 1024  // Maximum vote hashes to keep in the known list (prevent DOS)
 )
 
 func init() {
@@ -33,11 +35,13 @@ func init() {
 type Peer struct {
 	*p2p.Peer
 	*rpc.RPC
-	logger log15.Logger
+	logger  log15.Logger
+	version uint // negotiated protocol version
 
 	createdTime mclock.AbsTime
 	knownTxs    *lru.Cache
 	knownBlocks *lru.Cache
+	knownVotes  *lru.Cache
 	head        struct {
 		sync.Mutex
 		id         powerplay.Bytes32
@@ -45,7 +49,7 @@ type Peer struct {
 	}
 }
 
-func newPeer(peer *p2p.Peer, rw p2p.MsgReadWriter) *Peer {
+func newPeer(peer *p2p.Peer, rw p2p.MsgReadWriter, version uint) *Peer {
 	dir := "outbound"
 	if peer.Inbound() {
 		dir = "inbound"
@@ -56,16 +60,25 @@ func newPeer(peer *p2p.Peer, rw p2p.MsgReadWriter) *Peer {
 	}
 	knownTxs, _ := lru.New(maxKnownTxs)
 	knownBlocks, _ := lru.New(maxKnownBlocks)
+	knownVotes, _ := lru.New(maxKnownVotes)
 	return &Peer{
 		Peer:        peer,
 		RPC:         rpc.New(peer, rw),
 		logger:      log.New(ctx...),
+		version:     version,
 		createdTime: mclock.Now(),
 		knownTxs:    knownTxs,
 		knownBlocks: knownBlocks,
+		knownVotes:  knownVotes,
 	}
 }
 
+// SupportsFinality returns whether the peer negotiated a protocol version with
+// MsgNewVote and MsgNewEvidence.
+func (p *Peer) SupportsFinality() bool {
+	return p.version >= proto.Version
+}
+
 // Head returns head block ID and total score.
 func (p *Peer) Head() (id powerplay.Bytes32, totalScore uint64) {
 	p.head.Lock()
@@ -92,6 +105,11 @@ func (p *Peer) MarkBlock(id powerplay.Bytes32) {
 	p.knownBlocks.Add(id, struct{}{})
 }
 
+// MarkVote marks a vote to known.
+func (p *Peer) MarkVote(hash powerplay.Bytes32) {
+	p.knownVotes.Add(hash, struct{}{})
+}
+
 // IsTransactionKnown returns if the transaction is known.
 func (p *Peer) IsTransactionKnown(id powerplay.Bytes32) bool {
 	return p.knownTxs.Contains(id)
@@ -102,6 +120,11 @@ func (p *Peer) IsBlockKnown(id powerplay.Bytes32) bool {
 	return p.knownBlocks.Contains(id)
 }
 
+// IsVoteKnown returns if the vote is known.
+func (p *Peer) IsVoteKnown(hash powerplay.Bytes32) bool {
+	return p.knownVotes.Contains(hash)
+}
+
 // Duration returns duration of connection.
 func (p *Peer) Duration() mclock.AbsTime {
 	return mclock.Now() - p.createdTime
@@ -0,0 +1,22 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/playmakerchain/powerplay/comm/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerSupportsFinality(t *testing.T) {
+	p := p2p.NewPeer(discover.NodeID{}, "test", nil)
+
+	assert.False(t, newPeer(p, nil, proto.Version1).SupportsFinality())
+	assert.True(t, newPeer(p, nil, proto.Version).SupportsFinality())
+}
@@ -13,9 +13,13 @@ import (
 // Constants
 const (
 	Name              = "powerplay"
-	Version    uint   = 1
-	Length     uint64 = 8
+	Version    uint   = 2
+	Length     uint64 = 10
 	MaxMsgSize        = 10 * 1024 * 1024
+
+	// Version1 the legacy version, without MsgNewVote and MsgNewEvidence.
+	Version1       uint   = 1
+	Version1Length uint64 = 8
 )
 
 // Protocol messages of powerplay
@@ -28,6 +32,8 @@ const (
 	MsgGetBlockIDByNumber
 	MsgGetBlocksFromNumber // fetch blocks from given number (including given number)
 	MsgGetTxs
+	MsgNewVote
+	MsgNewEvidence
 )
 
 // MsgName convert msg code to string.
@@ -49,6 +55,10 @@ func MsgName(msgCode uint64) string {
 		return "MsgGetBlocksFromNumber"
 	case MsgGetTxs:
 		return "MsgGetTxs"
+	case MsgNewVote:
+		return "MsgNewVote"
+	case MsgNewEvidence:
+		return "MsgNewEvidence"
 	default:
 		return fmt.Sprintf("unknown msg code(%v)", msgCode)
 	}
@@ -10,7 +10,9 @@ import (
 	"context"
 
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/playmakerchain/powerplay/bft"
 	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/equivocation"
 	"github.com/playmakerchain/powerplay/powerplay"
 	"github.com/playmakerchain/powerplay/tx"
 )
@@ -56,6 +58,16 @@ func NotifyNewTx(ctx context.Context, rpc RPC, tx *tx.Transaction) error {
 	return rpc.Notify(ctx, MsgNewTx, tx)
 }
 
+// NotifyNewVote notify new finality vote to remote peer.
+func NotifyNewVote(ctx context.Context, rpc RPC, vote *bft.Vote) error {
+	return rpc.Notify(ctx, MsgNewVote, vote)
+}
+
+// NotifyNewEvidence notify new equivocation evidence to remote peer.
+func NotifyNewEvidence(ctx context.Context, rpc RPC, ev *equivocation.Evidence) error {
+	return rpc.Notify(ctx, MsgNewEvidence, ev)
+}
+
 // GetBlockByID query block from remote peer by given block ID.
 // It may return nil block even no error.
 func GetBlockByID(ctx context.Context, rpc RPC, id powerplay.Bytes32) (rlp.RawValue, error) {
@@ -9,6 +9,8 @@ package consensus
 import (
 	"github.com/playmakerchain/powerplay/block"
 	"github.com/playmakerchain/powerplay/chain"
+	"github.com/playmakerchain/powerplay/poa"
+	"github.com/playmakerchain/powerplay/powerplay"
 	"github.com/playmakerchain/powerplay/runtime"
 	"github.com/playmakerchain/powerplay/state"
 	"github.com/playmakerchain/powerplay/tx"
@@ -20,13 +22,15 @@ import (
 type Consensus struct {
 	chain        *chain.Chain
 	stateCreator *state.Creator
+	forkConfig   powerplay.ForkConfig
 }
 
 // New create a Consensus instance.
 func New(chain *chain.Chain, stateCreator *state.Creator) *Consensus {
 	return &Consensus{
 		chain:        chain,
-		stateCreator: stateCreator}
+		stateCreator: stateCreator,
+		forkConfig:   powerplay.GetForkConfig(chain.GenesisBlock().Header().ID())}
 }
 
 // Process process a block.
@@ -79,7 +83,8 @@ func (c *Consensus) NewRuntimeForReplay(header *block.Header, skipPoA bool) (*ru
 		return nil, err
 	}
 	if !skipPoA {
-		if err := c.validateProposer(header, parentHeader, state); err != nil {
+		params := poa.LoadParams(state, c.forkConfig, header.Number())
+		if err := c.validateProposer(header, parentHeader, state, params); err != nil {
 			return nil, err
 		}
 	}
@@ -28,11 +28,13 @@ func (c *Consensus) validate(
 ) (*state.Stage, tx.Receipts, error) {
 	header := block.Header()
 
-	if err := c.validateBlockHeader(header, parentHeader, nowTimestamp); err != nil {
+	params := poa.LoadParams(state, c.forkConfig, header.Number())
+
+	if err := c.validateBlockHeader(header, parentHeader, nowTimestamp, params); err != nil {
 		return nil, nil, err
 	}
 
-	if err := c.validateProposer(header, parentHeader, state); err != nil {
+	if err := c.validateProposer(header, parentHeader, state, params); err != nil {
 		return nil, nil, err
 	}
 
@@ -48,16 +50,16 @@ func (c *Consensus) validate(
 	return stage, receipts, nil
 }
 
-func (c *Consensus) validateBlockHeader(header *block.Header, parent *block.Header, nowTimestamp uint64) error {
+func (c *Consensus) validateBlockHeader(header *block.Header, parent *block.Header, nowTimestamp uint64, params poa.Params) error {
 	if header.Timestamp() <= parent.Timestamp() {
 		return consensusError(fmt.Sprintf("block timestamp behind parents: parent %v, current %v", parent.Timestamp(), header.Timestamp()))
 	}
 
-	if (header.Timestamp()-parent.Timestamp())%powerplay.BlockInterval != 0 {
+	if (header.Timestamp()-parent.Timestamp())%params.BlockInterval != 0 {
 		return consensusError(fmt.Sprintf("block interval not rounded: parent %v, current %v", parent.Timestamp(), header.Timestamp()))
 	}
 
-	if header.Timestamp() > nowTimestamp+powerplay.BlockInterval {
+	if header.Timestamp() > nowTimestamp+params.BlockInterval {
 		return errFutureBlock
 	}
 
@@ -76,7 +78,7 @@ func (c *Consensus) validateBlockHeader(header *block.Header, parent *block.Head
 	return nil
 }
 
-func (c *Consensus) validateProposer(header *block.Header, parent *block.Header, st *state.State) error {
+func (c *Consensus) validateProposer(header *block.Header, parent *block.Header, st *state.State, params poa.Params) error {
 	signer, err := header.Signer()
 	if err != nil {
 		return consensusError(fmt.Sprintf("block signer unavailable: %v", err))
@@ -85,7 +87,7 @@ func (c *Consensus) validateProposer(header *block.Header, parent *block.Header,
 	authority := builtin.Authority.Native(st)
 	endorsement := builtin.Params.Native(st).Get(powerplay.KeyProposerEndorsement)
 
-	candidates := authority.Candidates(endorsement, powerplay.MaxBlockProposers)
+	candidates := authority.Candidates(endorsement, params.MaxBlockProposers)
 	proposers := make([]poa.Proposer, 0, len(candidates))
 	for _, c := range candidates {
 		proposers = append(proposers, poa.Proposer{
@@ -94,7 +96,7 @@ func (c *Consensus) validateProposer(header *block.Header, parent *block.Header,
 		})
 	}
 
-	sched, err := poa.NewScheduler(signer, proposers, parent.Number(), parent.Timestamp())
+	sched, err := poa.NewScheduler(signer, proposers, parent.Number(), parent.Timestamp(), params)
 	if err != nil {
 		return consensusError(fmt.Sprintf("block signer invalid: %v %v", signer, err))
 	}
@@ -136,6 +138,8 @@ func (c *Consensus) validateBlockBody(blk *block.Block) error {
 			return consensusError(fmt.Sprintf("tx expired: ref %v, current %v, expiration %v", tx.BlockRef().Number(), header.Number(), tx.Expiration()))
 		case tx.HasReservedFields():
 			return consensusError(fmt.Sprintf("tx reserved fields not empty"))
+		case tx.Features() != 0 && header.Number() < c.forkConfig.FeeDelegation:
+			return consensusError(fmt.Sprintf("tx features not activated"))
 		}
 	}
 
@@ -175,7 +179,10 @@ func (c *Consensus) verifyBlock(blk *block.Block, state *state.State) (*state.St
 		return true, meta.Reverted, nil
 	}
 
-	for _, tx := range txs {
+	// execute txs speculatively in parallel, then commit results in order
+	specs := rt.Speculate(txs)
+
+	for i, tx := range txs {
 		// check if tx existed
 		if found, _, err := findTx(tx.ID()); err != nil {
 			return nil, nil, err
@@ -198,7 +205,7 @@ func (c *Consensus) verifyBlock(blk *block.Block, state *state.State) (*state.St
 			}
 		}
 
-		receipt, err := rt.ExecuteTransaction(tx)
+		receipt, err := rt.CommitSpeculation(specs[i])
 		if err != nil {
 			return nil, nil, err
 		}
@@ -0,0 +1,56 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package equivocation
+
+import (
+	"encoding/binary"
+
+	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/cache"
+	"github.com/playmakerchain/powerplay/powerplay"
+)
+
+// max count of recent slots to remember
+const maxRecentSlots = 4096
+
+// Detector detects conflicting headers by remembering slots occupied by recently seen headers.
+// It's thread-safe.
+type Detector struct {
+	slots *cache.RandCache // slot key -> header
+}
+
+// NewDetector create a detector.
+func NewDetector() *Detector {
+	return &Detector{cache.NewRandCache(maxRecentSlots)}
+}
+
+// Check remembers the header, and returns an evidence if it conflicts with any header seen before.
+// The header should be signature verified.
+func (d *Detector) Check(header *block.Header) (*Evidence, error) {
+	signer, err := header.Signer()
+	if err != nil {
+		return nil, err
+	}
+	parentID := header.ParentID()
+	var b12 [12]byte
+	binary.BigEndian.PutUint32(b12[:], header.Number())
+	binary.BigEndian.PutUint64(b12[4:], header.Timestamp())
+
+	var ev *Evidence
+	for _, key := range []powerplay.Bytes32{
+		powerplay.Blake2b(signer[:], parentID[:]),
+		powerplay.Blake2b(signer[:], b12[:]),
+	} {
+		if v, ok := d.slots.Get(key); ok {
+			if seen := v.(*block.Header); ev == nil && seen.ID() != header.ID() {
+				ev = NewEvidence(seen, header)
+			}
+			continue
+		}
+		d.slots.Set(key, header)
+	}
+	return ev, nil
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package equivocation
+
+import (
+	"crypto/ecdsa"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/lvldb"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/stretchr/testify/assert"
+)
+
+func newHeader(parentID powerplay.Bytes32, timestamp uint64, key *ecdsa.PrivateKey) *block.Header {
+	b := new(block.Builder).ParentID(parentID).Timestamp(timestamp).Build()
+	sig, _ := crypto.Sign(b.Header().SigningHash().Bytes(), key)
+	return b.WithSignature(sig).Header()
+}
+
+func TestDetector(t *testing.T) {
+	key1, _ := crypto.GenerateKey()
+	key2, _ := crypto.GenerateKey()
+	parent := powerplay.BytesToBytes32([]byte("parent"))
+	d := NewDetector()
+
+	h1 := newHeader(parent, 10, key1)
+	ev, err := d.Check(h1)
+	assert.Nil(t, err)
+	assert.Nil(t, ev)
+
+	ev, _ = d.Check(h1)
+	assert.Nil(t, ev, "same header seen again")
+
+	ev, _ = d.Check(newHeader(parent, 10, key2))
+	assert.Nil(t, ev, "different signer")
+
+	h2 := newHeader(parent, 20, key1)
+	ev, _ = d.Check(h2)
+	assert.NotNil(t, ev, "same parent")
+	assert.Nil(t, ev.Verify())
+	offender, err := ev.Offender()
+	assert.Nil(t, err)
+	assert.Equal(t, powerplay.Address(crypto.PubkeyToAddress(key1.PublicKey)), offender)
+
+	// same number and timestamp, upon another parent of the same height
+	ev, _ = d.Check(newHeader(powerplay.BytesToBytes32([]byte("other")), 10, key1))
+	assert.NotNil(t, ev, "same height and timestamp")
+	assert.Nil(t, ev.Verify())
+}
+
+func TestEvidence(t *testing.T) {
+	key1, _ := crypto.GenerateKey()
+	key2, _ := crypto.GenerateKey()
+	parent := powerplay.BytesToBytes32([]byte("parent"))
+
+	h1 := newHeader(parent, 10, key1)
+	h2 := newHeader(parent, 20, key1)
+	assert.Equal(t, NewEvidence(h1, h2).ID(), NewEvidence(h2, h1).ID(), "canonical order")
+
+	assert.NotNil(t, NewEvidence(h1, h1).Verify(), "identical headers")
+	assert.NotNil(t, NewEvidence(h1, newHeader(parent, 20, key2)).Verify(), "different signers")
+	assert.NotNil(t, NewEvidence(h1, newHeader(h1.ID(), 20, key1)).Verify(), "not conflict")
+
+	ev := NewEvidence(h1, h2)
+	ev.A, ev.B = ev.B, ev.A
+	assert.NotNil(t, ev.Verify(), "not in canonical order")
+}
+
+func TestPool(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	pool := NewPool(db)
+	key, _ := crypto.GenerateKey()
+	parent := powerplay.BytesToBytes32([]byte("parent"))
+	ev := NewEvidence(newHeader(parent, 10, key), newHeader(parent, 20, key))
+
+	added, err := pool.Add(ev)
+	assert.Nil(t, err)
+	assert.True(t, added)
+
+	added, err = pool.Add(ev)
+	assert.Nil(t, err)
+	assert.False(t, added, "known evidence")
+
+	_, err = pool.Add(NewEvidence(ev.A, ev.A))
+	assert.NotNil(t, err, "invalid evidence")
+
+	got, err := pool.Get(ev.ID())
+	assert.Nil(t, err)
+	assert.Equal(t, ev.ID(), got.ID())
+
+	_, err = pool.Get(powerplay.Bytes32{})
+	assert.True(t, pool.IsNotFound(err))
+
+	all, err := pool.All()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(all))
+}
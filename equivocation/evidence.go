@@ -0,0 +1,81 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package equivocation
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/powerplay"
+)
+
+// Evidence proves that a node master signed two conflicting blocks, which are different blocks
+// upon the same parent, or at the same height and timestamp.
+type Evidence struct {
+	A *block.Header
+	B *block.Header
+}
+
+// NewEvidence create an evidence of the two headers, in canonical order.
+func NewEvidence(a, b *block.Header) *Evidence {
+	aID, bID := a.ID(), b.ID()
+	if bytes.Compare(aID[:], bID[:]) > 0 {
+		a, b = b, a
+	}
+	return &Evidence{a, b}
+}
+
+// ID returns the identifier of the evidence.
+func (e *Evidence) ID() powerplay.Bytes32 {
+	aID, bID := e.A.ID(), e.B.ID()
+	return powerplay.Blake2b(aID[:], bID[:])
+}
+
+// Offender returns the node master who signed the conflicting blocks.
+func (e *Evidence) Offender() (powerplay.Address, error) {
+	return e.A.Signer()
+}
+
+// Verify checks that the evidence is valid.
+func (e *Evidence) Verify() error {
+	if e.A == nil || e.B == nil {
+		return errors.New("incomplete")
+	}
+	aID, bID := e.A.ID(), e.B.ID()
+	if bytes.Compare(aID[:], bID[:]) >= 0 {
+		return errors.New("headers identical or not in canonical order")
+	}
+	if !conflicts(e.A, e.B) {
+		return errors.New("headers not conflict")
+	}
+	aSigner, err := e.A.Signer()
+	if err != nil {
+		return errors.WithMessage(err, "signer")
+	}
+	bSigner, err := e.B.Signer()
+	if err != nil {
+		return errors.WithMessage(err, "signer")
+	}
+	if aSigner != bSigner {
+		return errors.New("headers signed by different signers")
+	}
+	return nil
+}
+
+func (e *Evidence) String() string {
+	offender, _ := e.Offender()
+	return fmt.Sprintf("Evidence(%v: %v %v)", offender, e.A.ID(), e.B.ID())
+}
+
+// conflicts returns whether the two different headers occupy the same slot.
+func conflicts(a, b *block.Header) bool {
+	if a.ParentID() == b.ParentID() {
+		return true
+	}
+	return a.Number() == b.Number() && a.Timestamp() == b.Timestamp()
+}
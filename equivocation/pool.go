@@ -0,0 +1,88 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package equivocation
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/playmakerchain/powerplay/kv"
+	"github.com/playmakerchain/powerplay/powerplay"
+)
+
+var evidencePrefix = []byte("ev") // (prefix, evidence id) -> evidence
+
+// Pool persists verified evidences.
+type Pool struct {
+	kv   kv.GetPutter
+	lock sync.Mutex
+}
+
+// NewPool create an evidence pool persisted in kv.
+func NewPool(kv kv.GetPutter) *Pool {
+	return &Pool{kv: kv}
+}
+
+// Add verifies and saves the evidence. It returns false if the evidence is already known.
+func (p *Pool) Add(ev *Evidence) (bool, error) {
+	if err := ev.Verify(); err != nil {
+		return false, err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	key := evidenceKey(ev.ID())
+	if has, err := p.kv.Has(key); err != nil || has {
+		return false, err
+	}
+	data, err := rlp.EncodeToBytes(ev)
+	if err != nil {
+		return false, err
+	}
+	if err := p.kv.Put(key, data); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Get returns the evidence by ID.
+func (p *Pool) Get(id powerplay.Bytes32) (*Evidence, error) {
+	data, err := p.kv.Get(evidenceKey(id))
+	if err != nil {
+		return nil, err
+	}
+	var ev Evidence
+	if err := rlp.DecodeBytes(data, &ev); err != nil {
+		return nil, err
+	}
+	return &ev, nil
+}
+
+// All returns all evidences.
+func (p *Pool) All() ([]*Evidence, error) {
+	it := p.kv.NewIterator(*kv.NewRangeWithBytesPrefix(evidencePrefix))
+	defer it.Release()
+
+	var evs []*Evidence
+	for it.Next() {
+		var ev Evidence
+		if err := rlp.DecodeBytes(it.Value(), &ev); err != nil {
+			return nil, err
+		}
+		evs = append(evs, &ev)
+	}
+	return evs, it.Error()
+}
+
+// IsNotFound returns whether the error indicates evidence not found.
+func (p *Pool) IsNotFound(err error) bool {
+	return p.kv.IsNotFound(err)
+}
+
+func evidenceKey(id powerplay.Bytes32) []byte {
+	return append(append([]byte(nil), evidencePrefix...), id[:]...)
+}
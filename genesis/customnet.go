@@ -0,0 +1,200 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package genesis
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/playmakerchain/powerplay/builtin"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/state"
+	"github.com/playmakerchain/powerplay/tx"
+	"github.com/playmakerchain/powerplay/vm"
+)
+
+// CustomGenesis is user customized genesis, usually loaded from a json file.
+type CustomGenesis struct {
+	LaunchTime uint64                `json:"launchTime"`
+	GasLimit   uint64                `json:"gasLimit"`
+	ExtraData  string                `json:"extraData"`
+	Accounts   []Account             `json:"accounts"`
+	Authority  []Authority           `json:"authority"`
+	Executor   Executor              `json:"executor"`
+	Params     Params                `json:"params"`
+	ForkConfig *powerplay.ForkConfig `json:"forkConfig"` // forks omitted are never activated
+}
+
+// Account is the account will set to the genesis block.
+type Account struct {
+	Address powerplay.Address            `json:"address"`
+	Balance *math.HexOrDecimal256        `json:"balance"`
+	Energy  *math.HexOrDecimal256        `json:"energy"`
+	Code    hexutil.Bytes                `json:"code"`
+	Storage map[string]powerplay.Bytes32 `json:"storage"`
+}
+
+// Authority is the authority node info.
+type Authority struct {
+	MasterAddress   powerplay.Address `json:"masterAddress"`
+	EndorsorAddress powerplay.Address `json:"endorsorAddress"`
+	Identity        powerplay.Bytes32 `json:"identity"`
+}
+
+// Executor is the params for executor info.
+type Executor struct {
+	Approvers []Approver `json:"approvers"`
+}
+
+// Approver is the approver info for executor contract.
+type Approver struct {
+	Address  powerplay.Address `json:"address"`
+	Identity string            `json:"identity"`
+}
+
+// Params means the chain params for params contract. Unset ones take initial values.
+type Params struct {
+	RewardRatio         *math.HexOrDecimal256 `json:"rewardRatio"`
+	BaseGasPrice        *math.HexOrDecimal256 `json:"baseGasPrice"`
+	ProposerEndorsement *math.HexOrDecimal256 `json:"proposerEndorsement"`
+	ExecutorAddress     *powerplay.Address    `json:"executorAddress"`
+	BlockInterval       *math.HexOrDecimal256 `json:"blockInterval"`
+	MaxBlockProposers   *math.HexOrDecimal256 `json:"maxBlockProposers"`
+}
+
+// NewCustomNet create custom network genesis.
+// Fork heights in the genesis are not applied here, see RegisterForkConfig.
+func NewCustomNet(gen *CustomGenesis) (*Genesis, error) {
+	if gen.LaunchTime == 0 {
+		return nil, errors.New("launch time not specified")
+	}
+	if len(gen.Authority) == 0 {
+		return nil, errors.New("no authority node")
+	}
+	if len(gen.ExtraData) > 28 {
+		return nil, errors.New("extra data too long, at most 28 bytes")
+	}
+	if gen.Params.ExecutorAddress != nil && len(gen.Executor.Approvers) > 0 {
+		return nil, errors.New("executor approvers conflict with external executor address")
+	}
+
+	launchTime := gen.LaunchTime
+	gasLimit := gen.GasLimit
+	if gasLimit == 0 {
+		gasLimit = powerplay.InitialGasLimit
+	} else if gasLimit < powerplay.MinGasLimit {
+		return nil, fmt.Errorf("gas limit should not be less than %v", powerplay.MinGasLimit)
+	}
+
+	builder := new(Builder).
+		Timestamp(launchTime).
+		GasLimit(gasLimit).
+		State(func(state *state.State) error {
+			// alloc precompiled contracts
+			for addr := range vm.PrecompiledContractsByzantium {
+				state.SetCode(powerplay.Address(addr), emptyRuntimeBytecode)
+			}
+
+			// alloc builtin contracts
+			state.SetCode(builtin.Authority.Address, builtin.Authority.RuntimeBytecodes())
+			state.SetCode(builtin.Energy.Address, builtin.Energy.RuntimeBytecodes())
+			state.SetCode(builtin.Executor.Address, builtin.Executor.RuntimeBytecodes())
+			state.SetCode(builtin.Extension.Address, builtin.Extension.RuntimeBytecodes())
+			state.SetCode(builtin.Params.Address, builtin.Params.RuntimeBytecodes())
+			state.SetCode(builtin.Prototype.Address, builtin.Prototype.RuntimeBytecodes())
+
+			tokenSupply := &big.Int{}
+			energySupply := &big.Int{}
+			for _, a := range gen.Accounts {
+				if b := (*big.Int)(a.Balance); b != nil {
+					if b.Sign() < 0 {
+						return fmt.Errorf("%s: balance must be a non-negative integer", a.Address)
+					}
+					tokenSupply.Add(tokenSupply, b)
+					state.SetBalance(a.Address, b)
+				}
+				energy := &big.Int{}
+				if e := (*big.Int)(a.Energy); e != nil {
+					if e.Sign() < 0 {
+						return fmt.Errorf("%s: energy must be a non-negative integer", a.Address)
+					}
+					energySupply.Add(energySupply, e)
+					energy = e
+				}
+				state.SetEnergy(a.Address, energy, launchTime)
+				if len(a.Code) > 0 {
+					state.SetCode(a.Address, a.Code)
+				}
+				for k, v := range a.Storage {
+					key, err := powerplay.ParseBytes32(k)
+					if err != nil {
+						return fmt.Errorf("%s: invalid storage key %v: %v", a.Address, k, err)
+					}
+					state.SetStorage(a.Address, key, v)
+				}
+			}
+
+			builtin.Energy.Native(state, launchTime).SetInitialSupply(tokenSupply, energySupply)
+			return nil
+		})
+
+	///// initialize builtin contracts
+
+	// initialize params
+	executor := builtin.Executor.Address
+	if gen.Params.ExecutorAddress != nil {
+		executor = *gen.Params.ExecutorAddress
+	}
+	data := mustEncodeInput(builtin.Params.ABI, "set", powerplay.KeyExecutorAddress, new(big.Int).SetBytes(executor[:]))
+	builder.Call(tx.NewClause(&builtin.Params.Address).WithData(data), powerplay.Address{})
+
+	for _, p := range []struct {
+		key   powerplay.Bytes32
+		value *math.HexOrDecimal256
+		def   *big.Int
+	}{
+		{powerplay.KeyRewardRatio, gen.Params.RewardRatio, powerplay.InitialRewardRatio},
+		{powerplay.KeyBaseGasPrice, gen.Params.BaseGasPrice, powerplay.InitialBaseGasPrice},
+		{powerplay.KeyProposerEndorsement, gen.Params.ProposerEndorsement, powerplay.InitialProposerEndorsement},
+		{powerplay.KeyBlockInterval, gen.Params.BlockInterval, nil},
+		{powerplay.KeyMaxBlockProposers, gen.Params.MaxBlockProposers, nil},
+	} {
+		value := p.def
+		if p.value != nil {
+			value = (*big.Int)(p.value)
+		}
+		if value == nil {
+			continue
+		}
+		data := mustEncodeInput(builtin.Params.ABI, "set", p.key, value)
+		builder.Call(tx.NewClause(&builtin.Params.Address).WithData(data), executor)
+	}
+
+	// add initial authority nodes
+	for _, anode := range gen.Authority {
+		data := mustEncodeInput(builtin.Authority.ABI, "add", anode.MasterAddress, anode.EndorsorAddress, anode.Identity)
+		builder.Call(tx.NewClause(&builtin.Authority.Address).WithData(data), executor)
+	}
+
+	// add initial approvers
+	for _, approver := range gen.Executor.Approvers {
+		data := mustEncodeInput(builtin.Executor.ABI, "addApprover", approver.Address, powerplay.BytesToBytes32([]byte(approver.Identity)))
+		builder.Call(tx.NewClause(&builtin.Executor.Address).WithData(data), executor)
+	}
+
+	var extra [28]byte
+	copy(extra[:], gen.ExtraData)
+	builder.ExtraData(extra)
+
+	id, err := builder.ComputeID()
+	if err != nil {
+		return nil, err
+	}
+	return &Genesis{builder, id, "customnet"}, nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package genesis_test
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/playmakerchain/powerplay/builtin"
+	"github.com/playmakerchain/powerplay/genesis"
+	"github.com/playmakerchain/powerplay/lvldb"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/state"
+	"github.com/stretchr/testify/assert"
+)
+
+const customGenesisJSON = `{
+	"launchTime": 1526400000,
+	"gasLimit": 20000000,
+	"extraData": "my consortium",
+	"accounts": [
+		{
+			"address": "0x7567d83b7b8d80addcb281a71d54fc7b3364ffed",
+			"balance": "0x14adf4b7320334b9000000",
+			"energy": "1000"
+		},
+		{
+			"address": "0x00000000000000000000000000000000000000aa",
+			"code": "0x6060604052600256",
+			"storage": {
+				"0x0000000000000000000000000000000000000000000000000000000000000001": "0x0000000000000000000000000000000000000000000000000000000000000002"
+			}
+		}
+	],
+	"authority": [
+		{
+			"masterAddress": "0x435933c8064b4ae76be665428e0307ef2ccfbd68",
+			"endorsorAddress": "0x7567d83b7b8d80addcb281a71d54fc7b3364ffed",
+			"identity": "0x000000000000000068747470733a2f2f636f6e6e65782e76656368612e696e2f"
+		}
+	],
+	"executor": {
+		"approvers": [
+			{"address": "0x199b836d8a57365baccd4f371c1fabb7be77d389", "identity": "approver"}
+		]
+	},
+	"params": {
+		"baseGasPrice": "1000",
+		"blockInterval": "5"
+	},
+	"forkConfig": {
+		"fixTransferLog": 0,
+		"governedSchedule": 100
+	}
+}`
+
+func TestCustomNet(t *testing.T) {
+	var gen genesis.CustomGenesis
+	assert.Nil(t, json.Unmarshal([]byte(customGenesisJSON), &gen))
+	assert.Equal(t, uint32(0), gen.ForkConfig.FixTransferLog)
+	assert.Equal(t, uint32(100), gen.ForkConfig.GovernedSchedule)
+	assert.Equal(t, uint32(math.MaxUint32), gen.ForkConfig.FeeDelegation, "omitted fork never activated")
+
+	gene, err := genesis.NewCustomNet(&gen)
+	assert.Nil(t, err)
+
+	kv, _ := lvldb.NewMem()
+	b0, _, err := gene.Build(state.NewCreator(kv))
+	assert.Nil(t, err)
+	assert.Equal(t, gene.ID(), b0.Header().ID())
+	assert.Equal(t, uint64(20000000), b0.Header().GasLimit())
+
+	st, _ := state.New(b0.Header().StateRoot(), kv)
+	acc := powerplay.MustParseAddress("0x7567d83b7b8d80addcb281a71d54fc7b3364ffed")
+	balance, _ := new(big.Int).SetString("14adf4b7320334b9000000", 16)
+	assert.Equal(t, balance, st.GetBalance(acc))
+	assert.Equal(t, big.NewInt(1000), st.GetEnergy(acc, b0.Header().Timestamp()))
+
+	contract := powerplay.MustParseAddress("0x00000000000000000000000000000000000000aa")
+	assert.NotEmpty(t, st.GetCode(contract))
+	assert.Equal(t, powerplay.BytesToBytes32([]byte{2}), st.GetStorage(contract, powerplay.BytesToBytes32([]byte{1})))
+
+	params := builtin.Params.Native(st)
+	assert.Equal(t, big.NewInt(1000), params.Get(powerplay.KeyBaseGasPrice))
+	assert.Equal(t, powerplay.InitialRewardRatio, params.Get(powerplay.KeyRewardRatio))
+	assert.Equal(t, big.NewInt(5), params.Get(powerplay.KeyBlockInterval))
+	assert.Equal(t, 0, params.Get(powerplay.KeyMaxBlockProposers).Sign())
+
+	listed, _, _, _ := builtin.Authority.Native(st).Get(powerplay.MustParseAddress("0x435933c8064b4ae76be665428e0307ef2ccfbd68"))
+	assert.True(t, listed)
+	assert.Nil(t, st.Err())
+}
+
+func TestCustomNetInvalid(t *testing.T) {
+	var gen genesis.CustomGenesis
+	assert.Nil(t, json.Unmarshal([]byte(customGenesisJSON), &gen))
+
+	invalid := gen
+	invalid.LaunchTime = 0
+	_, err := genesis.NewCustomNet(&invalid)
+	assert.NotNil(t, err, "no launch time")
+
+	invalid = gen
+	invalid.Authority = nil
+	_, err = genesis.NewCustomNet(&invalid)
+	assert.NotNil(t, err, "no authority")
+
+	invalid = gen
+	invalid.ExtraData = "extra data longer than 28 bytes"
+	_, err = genesis.NewCustomNet(&invalid)
+	assert.NotNil(t, err, "extra data too long")
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package keystore
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	ethkeystore "github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pborman/uuid"
+	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/powerplay"
+)
+
+// Account an account stored in keystore.
+type Account struct {
+	Address powerplay.Address
+	Path    string // path of the key file
+}
+
+// Keystore manages encrypted accounts in a directory.
+// Each account is stored in a scrypt encrypted key file, compatible with Ethereum keystore.
+type Keystore struct {
+	dir     string
+	scryptN int
+	scryptP int
+}
+
+// New create a keystore instance on the directory, using standard scrypt parameters.
+func New(dir string) *Keystore {
+	return &Keystore{dir, ethkeystore.StandardScryptN, ethkeystore.StandardScryptP}
+}
+
+// NewLight create a keystore instance using light scrypt parameters, which is fast but less secure.
+func NewLight(dir string) *Keystore {
+	return &Keystore{dir, ethkeystore.LightScryptN, ethkeystore.LightScryptP}
+}
+
+// Accounts returns accounts in keystore, sorted by file name.
+// Files not in keystore format are skipped.
+func (ks *Keystore) Accounts() ([]Account, error) {
+	files, err := ioutil.ReadDir(ks.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name() < files[j].Name() })
+
+	var accounts []Account
+	for _, f := range files {
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(ks.dir, f.Name())
+		addr, err := readAddress(path)
+		if err != nil {
+			continue
+		}
+		accounts = append(accounts, Account{addr, path})
+	}
+	return accounts, nil
+}
+
+// Find finds the account with the address.
+func (ks *Keystore) Find(addr powerplay.Address) (Account, error) {
+	accounts, err := ks.Accounts()
+	if err != nil {
+		return Account{}, err
+	}
+	for _, acc := range accounts {
+		if acc.Address == addr {
+			return acc, nil
+		}
+	}
+	return Account{}, fmt.Errorf("account %v not found", addr)
+}
+
+// Create generates a new account encrypted with the password.
+func (ks *Keystore) Create(password string) (Account, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return Account{}, err
+	}
+	return ks.Import(key, password)
+}
+
+// Import stores the private key encrypted with the password.
+func (ks *Keystore) Import(key *ecdsa.PrivateKey, password string) (Account, error) {
+	addr := powerplay.Address(crypto.PubkeyToAddress(key.PublicKey))
+	if _, err := ks.Find(addr); err == nil {
+		return Account{}, fmt.Errorf("account %v already exists", addr)
+	}
+
+	keyjson, err := ethkeystore.EncryptKey(&ethkeystore.Key{
+		Id:         uuid.NewRandom(),
+		Address:    crypto.PubkeyToAddress(key.PublicKey),
+		PrivateKey: key,
+	}, password, ks.scryptN, ks.scryptP)
+	if err != nil {
+		return Account{}, err
+	}
+	if err := os.MkdirAll(ks.dir, 0700); err != nil {
+		return Account{}, err
+	}
+
+	path := filepath.Join(ks.dir, keyFileName(addr))
+	if err := ioutil.WriteFile(path, keyjson, 0600); err != nil {
+		return Account{}, err
+	}
+	return Account{addr, path}, nil
+}
+
+// Unlock decrypts the private key of the account.
+func (ks *Keystore) Unlock(addr powerplay.Address, password string) (*ecdsa.PrivateKey, error) {
+	acc, err := ks.Find(addr)
+	if err != nil {
+		return nil, err
+	}
+	keyjson, err := ioutil.ReadFile(acc.Path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ethkeystore.DecryptKey(keyjson, password)
+	if err != nil {
+		return nil, errors.WithMessage(err, "decrypt key")
+	}
+	return key.PrivateKey, nil
+}
+
+// ReadPasswordFile reads password from the first line of the file.
+func ReadPasswordFile(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(strings.SplitN(string(data), "\n", 2)[0], "\r"), nil
+}
+
+func readAddress(path string) (powerplay.Address, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return powerplay.Address{}, err
+	}
+	var key struct {
+		Address string `json:"address"`
+	}
+	if err := json.Unmarshal(data, &key); err != nil {
+		return powerplay.Address{}, err
+	}
+	return powerplay.ParseAddress(key.Address)
+}
+
+// keyFileName names the key file in the same way as Ethereum keystore.
+func keyFileName(addr powerplay.Address) string {
+	ts := time.Now().UTC()
+	return fmt.Sprintf("UTC--%s--%x", ts.Format("2006-01-02T15-04-05.000000000Z"), addr[:])
+}
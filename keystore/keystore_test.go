@@ -0,0 +1,83 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package keystore
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeystore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keystore")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	ks := NewLight(dir)
+	accounts, err := ks.Accounts()
+	assert.Nil(t, err)
+	assert.Empty(t, accounts)
+
+	acc1, err := ks.Create("pass1")
+	assert.Nil(t, err)
+	acc2, err := ks.Create("pass2")
+	assert.Nil(t, err)
+
+	accounts, err = ks.Accounts()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(accounts))
+
+	key, err := ks.Unlock(acc1.Address, "pass1")
+	assert.Nil(t, err)
+	assert.Equal(t, acc1.Address, powerplay.Address(crypto.PubkeyToAddress(key.PublicKey)))
+
+	_, err = ks.Unlock(acc2.Address, "pass1")
+	assert.NotNil(t, err, "should fail with wrong password")
+
+	_, err = ks.Import(key, "pass")
+	assert.NotNil(t, err, "should not import existing account")
+}
+
+func TestDeriveKey(t *testing.T) {
+	// BIP32 test vector 1
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	tests := []struct {
+		path string
+		key  string
+	}{
+		{"m/0'", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{"m/0'/1/2'/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+	}
+	for _, tt := range tests {
+		indices, err := ParseDerivationPath(tt.path)
+		assert.Nil(t, err)
+		key, err := deriveKey(seed, indices)
+		assert.Nil(t, err)
+		assert.Equal(t, tt.key, hex.EncodeToString(crypto.FromECDSA(key)))
+	}
+
+	_, err := ParseDerivationPath("44'/0")
+	assert.NotNil(t, err)
+}
+
+func TestDeriveFromMnemonic(t *testing.T) {
+	mnemonic, err := NewMnemonic()
+	assert.Nil(t, err)
+
+	key1, err := DeriveFromMnemonic(mnemonic, "", DefaultDerivationPath+"/0")
+	assert.Nil(t, err)
+	key2, err := DeriveFromMnemonic(mnemonic, "", DefaultDerivationPath+"/1")
+	assert.Nil(t, err)
+	assert.NotEqual(t, crypto.FromECDSA(key1), crypto.FromECDSA(key2))
+
+	_, err = DeriveFromMnemonic("invalid mnemonic words", "", DefaultDerivationPath+"/0")
+	assert.NotNil(t, err)
+}
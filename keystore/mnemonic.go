@@ -0,0 +1,122 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package keystore
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	bip39 "github.com/tyler-smith/go-bip39"
+)
+
+// DefaultDerivationPath the BIP44 path to derive accounts from mnemonic, with coin type 818
+// inherited from VeChainThor. The account index is appended as the last level.
+const DefaultDerivationPath = "m/44'/818'/0'/0"
+
+const hardenedOffset = 0x80000000
+
+// NewMnemonic generates a BIP39 mnemonic of 12 words.
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(128)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// DeriveFromMnemonic derives the private key from BIP39 mnemonic and passphrase, at the BIP32 path.
+func DeriveFromMnemonic(mnemonic, passphrase, path string) (*ecdsa.PrivateKey, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(strings.Join(strings.Fields(mnemonic), " "), passphrase)
+	if err != nil {
+		return nil, errors.WithMessage(err, "mnemonic")
+	}
+	indices, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return deriveKey(seed, indices)
+}
+
+// ParseDerivationPath parses BIP32 path like m/44'/818'/0'/0/0 into child indices.
+func ParseDerivationPath(path string) ([]uint32, error) {
+	parts := strings.Split(strings.TrimSpace(path), "/")
+	if len(parts) == 0 || parts[0] != "m" {
+		return nil, errors.New("derivation path should start with 'm'")
+	}
+	indices := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		var offset uint32
+		if strings.HasSuffix(part, "'") || strings.HasSuffix(part, "H") {
+			offset = hardenedOffset
+			part = part[:len(part)-1]
+		}
+		i, err := strconv.ParseUint(part, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid derivation path component %q", part)
+		}
+		indices = append(indices, uint32(i)+offset)
+	}
+	return indices, nil
+}
+
+// deriveKey derives private key from seed by BIP32.
+func deriveKey(seed []byte, indices []uint32) (*ecdsa.PrivateKey, error) {
+	key, chainCode := splitHMAC([]byte("Bitcoin seed"), seed)
+	for _, index := range indices {
+		var err error
+		if key, chainCode, err = deriveChild(key, chainCode, index); err != nil {
+			return nil, err
+		}
+	}
+	return crypto.ToECDSA(key)
+}
+
+// deriveChild computes private child key of the extended private key.
+func deriveChild(key, chainCode []byte, index uint32) ([]byte, []byte, error) {
+	var data []byte
+	if index >= hardenedOffset {
+		data = append([]byte{0}, key...)
+	} else {
+		priv, err := crypto.ToECDSA(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		data = crypto.CompressPubkey(&priv.PublicKey)
+	}
+	var indexBytes [4]byte
+	binary.BigEndian.PutUint32(indexBytes[:], index)
+	data = append(data, indexBytes[:]...)
+
+	il, childChainCode := splitHMAC(chainCode, data)
+
+	n := crypto.S256().Params().N
+	ilNum := new(big.Int).SetBytes(il)
+	if ilNum.Cmp(n) >= 0 {
+		return nil, nil, errors.New("invalid child key")
+	}
+	childNum := ilNum.Add(ilNum, new(big.Int).SetBytes(key))
+	childNum.Mod(childNum, n)
+	if childNum.Sign() == 0 {
+		return nil, nil, errors.New("invalid child key")
+	}
+	return math.PaddedBigBytes(childNum, 32), childChainCode, nil
+}
+
+func splitHMAC(key, data []byte) ([]byte, []byte) {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	return sum[:32], sum[32:]
+}
@@ -301,6 +301,75 @@ func (db *LogDB) queryTransfers(ctx context.Context, stmt string, args ...interf
 	return transfers, nil
 }
 
+// CountByBlock returns numbers of events and transfers stored for the given block.
+func (db *LogDB) CountByBlock(blockID powerplay.Bytes32) (events int, transfers int, err error) {
+	if err := db.db.QueryRow("SELECT COUNT(*) FROM event WHERE blockID = ?;", blockID.Bytes()).Scan(&events); err != nil {
+		return 0, 0, err
+	}
+	if err := db.db.QueryRow("SELECT COUNT(*) FROM transfer WHERE blockID = ?;", blockID.Bytes()).Scan(&transfers); err != nil {
+		return 0, 0, err
+	}
+	return events, transfers, nil
+}
+
+// Truncate removes logs of blocks above the given block number.
+func (db *LogDB) Truncate(blockNum uint32) error {
+	tx, err := db.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM event WHERE blockNumber > ?;", blockNum); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM transfer WHERE blockNumber > ?;", blockNum); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// dbstatTable the virtual table to measure sizes of tables.
+var dbstatTable = "dbstat"
+
+// TableStats describes statistics of a table.
+type TableStats struct {
+	Name string
+	Rows int64
+	Size int64 // size in bytes including indices, -1 if not available
+}
+
+// Stats returns statistics of tables and the total size of the log db.
+func (db *LogDB) Stats() (tables []*TableStats, total int64, err error) {
+	for _, name := range []string{"event", "transfer"} {
+		ts := &TableStats{Name: name, Size: -1}
+		if err := db.db.QueryRow("SELECT COUNT(*) FROM " + name + ";").Scan(&ts.Rows); err != nil {
+			return nil, 0, err
+		}
+		// dbstat is not available if sqlite compiled without SQLITE_ENABLE_DBSTAT_VTAB
+		var size sql.NullInt64
+		if err := db.db.QueryRow("SELECT SUM(pgsize) FROM "+dbstatTable+" WHERE name IN (SELECT name FROM sqlite_master WHERE tbl_name = ?);", name).Scan(&size); err == nil && size.Valid {
+			ts.Size = size.Int64
+		}
+		tables = append(tables, ts)
+	}
+
+	var pageCount, pageSize int64
+	if err := db.db.QueryRow("PRAGMA page_count;").Scan(&pageCount); err != nil {
+		return nil, 0, err
+	}
+	if err := db.db.QueryRow("PRAGMA page_size;").Scan(&pageSize); err != nil {
+		return nil, 0, err
+	}
+	return tables, pageCount * pageSize, nil
+}
+
+// Vacuum rebuilds the log db to reclaim free space.
+func (db *LogDB) Vacuum() error {
+	_, err := db.db.Exec("VACUUM;")
+	return err
+}
+
 func topicValue(topic *powerplay.Bytes32) []byte {
 	if topic == nil {
 		return nil
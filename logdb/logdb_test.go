@@ -135,6 +135,42 @@ func TestTransfers(t *testing.T) {
 	assert.Equal(t, len(ts), count, "transfers searched")
 }
 
+func TestStats(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	header := new(block.Builder).Build().Header()
+	for i := 0; i < 10; i++ {
+		header = new(block.Builder).ParentID(header.ID()).Build().Header()
+		if err := db.Prepare(header).ForTransaction(powerplay.Bytes32{}, powerplay.Address{}).
+			Insert(tx.Events{&tx.Event{}}, tx.Transfers{&tx.Transfer{Amount: big.NewInt(1)}, &tx.Transfer{Amount: big.NewInt(2)}}).
+			Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tables, total, err := db.Stats()
+	assert.Nil(t, err)
+	assert.True(t, total > 0)
+	if assert.Len(t, tables, 2) {
+		assert.Equal(t, "event", tables[0].Name)
+		assert.Equal(t, int64(10), tables[0].Rows)
+		assert.Equal(t, "transfer", tables[1].Name)
+		assert.Equal(t, int64(20), tables[1].Rows)
+		for _, ts := range tables {
+			assert.True(t, ts.Size == -1 || ts.Size > 0, "size measured, or not available")
+		}
+	}
+
+	assert.Nil(t, db.Vacuum())
+	tables, _, err = db.Stats()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(20), tables[1].Rows, "rows kept after vacuum")
+}
+
 func home() (string, error) {
 	// try to get HOME env
 	if home := os.Getenv("HOME"); home != "" {
@@ -184,3 +220,37 @@ func BenchmarkLog(b *testing.B) {
 		}
 	}
 }
+
+func TestTruncate(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	txEvent := &tx.Event{Address: powerplay.BytesToAddress([]byte("addr"))}
+	header := new(block.Builder).Build().Header()
+	var ids []powerplay.Bytes32
+	for i := 0; i < 10; i++ {
+		if err := db.Prepare(header).ForTransaction(powerplay.BytesToBytes32([]byte("txID")), powerplay.BytesToAddress([]byte("txOrigin"))).
+			Insert(tx.Events{txEvent}, nil).Commit(); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, header.ID())
+		header = new(block.Builder).ParentID(header.ID()).Build().Header()
+	}
+
+	assert.Nil(t, db.Truncate(4))
+
+	es, err := db.FilterEvents(context.Background(), nil)
+	assert.Nil(t, err)
+	// block numbers start from 1
+	assert.Equal(t, 4, len(es))
+
+	n, _, err := db.CountByBlock(ids[3])
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+	n, _, err = db.CountByBlock(ids[4])
+	assert.Nil(t, err)
+	assert.Equal(t, 0, n)
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsWithoutDBStat(t *testing.T) {
+	// as if sqlite compiled without dbstat
+	defer func(name string) { dbstatTable = name }(dbstatTable)
+	dbstatTable = "no_dbstat"
+
+	db, err := NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	tables, total, err := db.Stats()
+	assert.Nil(t, err)
+	assert.True(t, total > 0)
+	assert.Len(t, tables, 2)
+	for _, ts := range tables {
+		assert.Equal(t, int64(-1), ts.Size, "size not available")
+		assert.Equal(t, int64(0), ts.Rows)
+	}
+}
@@ -102,6 +102,19 @@ func (ldb *LevelDB) Close() error {
 	return ldb.db.Close()
 }
 
+// Stats returns level db statistics of each level, as a human readable table.
+func (ldb *LevelDB) Stats() (string, error) {
+	return ldb.db.GetProperty("leveldb.stats")
+}
+
+// Compact compacts the underlying storage for the given key range.
+func (ldb *LevelDB) Compact(r kv.Range) error {
+	return ldb.db.CompactRange(util.Range{
+		Start: r.From,
+		Limit: r.To,
+	})
+}
+
 // NewBatch create a batch for writing ops.
 func (ldb *LevelDB) NewBatch() kv.Batch {
 	return &levelDBBatch{
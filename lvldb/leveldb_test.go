@@ -7,8 +7,10 @@
 package lvldb
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/playmakerchain/powerplay/kv"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -109,3 +111,34 @@ func TestLevelDBBach(t *testing.T) {
 		assert.Equal(t, tt.expected, tt.ret)
 	}
 }
+
+func TestStatsAndCompact(t *testing.T) {
+	db, err := NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for i := 0; i < 1000; i++ {
+		assert.Nil(t, db.Put([]byte(fmt.Sprintf("key%04d", i)), []byte("value")))
+	}
+	for i := 0; i < 500; i++ {
+		assert.Nil(t, db.Delete([]byte(fmt.Sprintf("key%04d", i))))
+	}
+
+	stats, err := db.Stats()
+	assert.Nil(t, err)
+	assert.NotEmpty(t, stats)
+
+	// whole range
+	assert.Nil(t, db.Compact(kv.Range{}))
+	has, err := db.Has([]byte("key0499"))
+	assert.Nil(t, err)
+	assert.False(t, has)
+	value, err := db.Get([]byte("key0500"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("value"), value)
+
+	// partial range
+	assert.Nil(t, db.Compact(kv.Range{From: []byte("key0500"), To: []byte("key0600")}))
+}
@@ -8,11 +8,16 @@ package packer
 
 import (
 	"crypto/ecdsa"
+	goruntime "runtime"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/mclock"
 
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/pkg/errors"
 	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/builtin"
 	"github.com/playmakerchain/powerplay/runtime"
+	"github.com/playmakerchain/powerplay/signer"
 	"github.com/playmakerchain/powerplay/state"
 	"github.com/playmakerchain/powerplay/powerplay"
 	"github.com/playmakerchain/powerplay/tx"
@@ -70,11 +75,18 @@ func (f *Flow) findTx(txID powerplay.Bytes32) (found bool, reverted bool, err er
 // If the tx is valid and can be executed on current state (regardless of VM error),
 // it will be adopted by the new block.
 func (f *Flow) Adopt(tx *tx.Transaction) error {
+	return f.adopt(tx, nil)
+}
+
+// adopt adopts the tx, whose result is taken from the speculation if not nil.
+func (f *Flow) adopt(tx *tx.Transaction, spec *runtime.Speculation) error {
 	switch {
 	case tx.ChainTag() != f.packer.chain.Tag():
 		return badTxError{"chain tag mismatch"}
 	case tx.HasReservedFields():
 		return badTxError{"reserved fields not empty"}
+	case tx.Features() != 0 && f.runtime.Context().Number < f.packer.forkConfig.FeeDelegation:
+		return errTxNotAdoptableNow
 	case f.runtime.Context().Number < tx.BlockRef().Number():
 		return errTxNotAdoptableNow
 	case tx.IsExpired(f.runtime.Context().Number):
@@ -110,7 +122,7 @@ func (f *Flow) Adopt(tx *tx.Transaction) error {
 	}
 
 	checkpoint := f.runtime.State().NewCheckpoint()
-	receipt, err := f.runtime.ExecuteTransaction(tx)
+	receipt, err := f.execute(tx, spec)
 	if err != nil {
 		// skip and revert state
 		f.runtime.State().RevertTo(checkpoint)
@@ -123,20 +135,96 @@ func (f *Flow) Adopt(tx *tx.Transaction) error {
 	return nil
 }
 
-// Pack build and sign the new block.
+func (f *Flow) execute(tx *tx.Transaction, spec *runtime.Speculation) (*tx.Receipt, error) {
+	if spec != nil {
+		return f.runtime.CommitSpeculation(spec)
+	}
+	return f.runtime.ExecuteTransaction(tx)
+}
+
+// AdoptPending adopts pending txs in the order decided by the packer's strategy, until the
+// block is full or the time budget is used up. Zero budget means no limit.
+// It returns IDs of txs which will never be adoptable, and should be removed from tx pool.
+func (f *Flow) AdoptPending(txs []*PendingTx, budget time.Duration) (toRemove []powerplay.Bytes32) {
+	startTime := mclock.Now()
+	baseGasPrice := builtin.Params.Native(f.runtime.State()).Get(powerplay.KeyBaseGasPrice)
+	for _, ptx := range txs {
+		if ptx.GasPrice == nil {
+			ptx.GasPrice = ptx.OverallGasPrice(baseGasPrice, f.parentHeader.Number(), f.runtime.Seeker().GetID)
+		}
+	}
+
+	ordered := f.packer.strategy.Order(txs)
+	// txs are executed speculatively in parallel by batch, then adopted in order
+	batchSize := goruntime.NumCPU() * 4
+	for len(ordered) > 0 {
+		batch := ordered
+		if len(batch) > batchSize {
+			batch = batch[:batchSize]
+		}
+		ordered = ordered[len(batch):]
+
+		batchTxs := make(tx.Transactions, 0, len(batch))
+		for _, ptx := range batch {
+			batchTxs = append(batchTxs, ptx.Transaction)
+		}
+		specs := f.runtime.Speculate(batchTxs)
+
+		for i, ptx := range batch {
+			if budget > 0 && time.Duration(mclock.Now()-startTime) > budget {
+				return
+			}
+			if err := f.adopt(ptx.Transaction, specs[i]); err != nil {
+				if IsGasLimitReached(err) {
+					return
+				}
+				if IsTxNotAdoptableNow(err) {
+					continue
+				}
+				toRemove = append(toRemove, ptx.ID())
+			}
+		}
+	}
+	return
+}
+
+// Pack build and sign the new block with the private key.
 func (f *Flow) Pack(privateKey *ecdsa.PrivateKey) (*block.Block, *state.Stage, tx.Receipts, error) {
-	if f.packer.nodeMaster != powerplay.Address(crypto.PubkeyToAddress(privateKey.PublicKey)) {
-		return nil, nil, nil, errors.New("private key mismatch")
+	return f.PackWithSigner(signer.NewKeySigner(privateKey))
+}
+
+// PackWithSigner build the new block and sign it by the signer, which may be remote.
+func (f *Flow) PackWithSigner(s signer.Signer) (*block.Block, *state.Stage, tx.Receipts, error) {
+	if f.packer.nodeMaster != s.Address() {
+		return nil, nil, nil, errors.New("signer mismatch")
 	}
 
-	if err := f.runtime.Seeker().Err(); err != nil {
+	newBlock, stage, err := f.build()
+	if err != nil {
 		return nil, nil, nil, err
 	}
 
+	sig, err := s.SignBlock(newBlock.Header())
+	if err != nil {
+		return nil, nil, nil, errors.WithMessage(err, "sign block")
+	}
+	newBlock = newBlock.WithSignature(sig)
+	if signerAddr, err := newBlock.Header().Signer(); err != nil || signerAddr != f.packer.nodeMaster {
+		return nil, nil, nil, errors.New("invalid block signature")
+	}
+	return newBlock, stage, f.receipts, nil
+}
+
+// build the new block without signature.
+func (f *Flow) build() (*block.Block, *state.Stage, error) {
+	if err := f.runtime.Seeker().Err(); err != nil {
+		return nil, nil, err
+	}
+
 	stage := f.runtime.State().Stage()
 	stateRoot, err := stage.Hash()
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, err
 	}
 
 	builder := new(block.Builder).
@@ -151,11 +239,5 @@ func (f *Flow) Pack(privateKey *ecdsa.PrivateKey) (*block.Block, *state.Stage, t
 	for _, tx := range f.txs {
 		builder.Transaction(tx)
 	}
-	newBlock := builder.Build()
-
-	sig, err := crypto.Sign(newBlock.Header().SigningHash().Bytes(), privateKey)
-	if err != nil {
-		return nil, nil, nil, err
-	}
-	return newBlock.WithSignature(sig), stage, f.receipts, nil
+	return builder.Build(), stage, nil
 }
@@ -0,0 +1,110 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package packer
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/playmakerchain/powerplay/powerplay"
+)
+
+// GasLimitStatus the state of GasLimitController.
+type GasLimitStatus struct {
+	Target   uint64 // current target gas limit, 0 if not yet measured
+	Min      uint64 // lower bound of target
+	Max      uint64 // upper bound of target, 0 means unbounded
+	Capacity uint64 // estimated gas can be executed within TargetPackingTime
+
+	// measurement of the last packed block
+	GasUsed  uint64
+	GasLimit uint64
+	Elapsed  time.Duration
+	Backlog  int // count of pending txs left in pool
+}
+
+// TargetPackingTime the packing time GasLimitController aims at.
+// It's well below powerplay.TolerableBlockPackingTime, which is the budget packing is cut at,
+// so that slowness can be measured before blocks are cut short.
+const TargetPackingTime = powerplay.TolerableBlockPackingTime / 2
+
+// GasLimitController adjusts target gas limit according to measured packing time and
+// pool backlog. The target is lowered once packing takes longer than TargetPackingTime,
+// and raised towards the capacity while blocks are well used and txs are left behind.
+type GasLimitController struct {
+	lock   sync.Mutex
+	status GasLimitStatus
+}
+
+// NewGasLimitController create a controller with target bounded within [min, max].
+// min defaults to powerplay.MinGasLimit, and max is unbounded if 0.
+func NewGasLimitController(min, max uint64) *GasLimitController {
+	if min < powerplay.MinGasLimit {
+		min = powerplay.MinGasLimit
+	}
+	if max != 0 && max < min {
+		max = min
+	}
+	return &GasLimitController{
+		status: GasLimitStatus{Min: min, Max: max},
+	}
+}
+
+// Update feeds measurement of a packed block, and returns the new target gas limit.
+func (c *GasLimitController) Update(gasUsed, gasLimit uint64, elapsed time.Duration, backlog int) uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	s := &c.status
+	s.GasUsed, s.GasLimit, s.Elapsed, s.Backlog = gasUsed, gasLimit, elapsed, backlog
+
+	target := s.Target
+	if target == 0 {
+		target = gasLimit
+	}
+
+	// the estimation is meaningless if too few gas used, unless it's already slow
+	if elapsed > 0 && gasUsed > 0 && (gasUsed > gasLimit/3 || elapsed > TargetPackingTime) {
+		capacity := float64(gasUsed) * float64(TargetPackingTime) / float64(elapsed)
+		if capacity >= math.MaxUint64 {
+			s.Capacity = math.MaxUint64
+		} else {
+			s.Capacity = uint64(capacity)
+		}
+
+		switch {
+		case elapsed > TargetPackingTime:
+			// too slow to pack, or cut short by the budget
+			target = s.Capacity
+		case backlog > 0 && gasUsed > gasLimit/2:
+			// demand exceeds, grow as long as it can be executed in time
+			if s.Capacity > target {
+				target = s.Capacity
+			}
+		default:
+			if s.Capacity < target {
+				target = s.Capacity
+			}
+		}
+	}
+
+	if target < s.Min {
+		target = s.Min
+	}
+	if s.Max != 0 && target > s.Max {
+		target = s.Max
+	}
+	s.Target = target
+	return target
+}
+
+// Status returns the current state of the controller.
+func (c *GasLimitController) Status() GasLimitStatus {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.status
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package packer_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/playmakerchain/powerplay/packer"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGasLimitController(t *testing.T) {
+	const (
+		gl   = uint64(10 * 1000 * 1000)
+		fast = packer.TargetPackingTime / 4
+		slow = packer.TargetPackingTime * 5 / 4
+	)
+
+	c := packer.NewGasLimitController(0, 50*1000*1000)
+	assert.Equal(t, powerplay.MinGasLimit, c.Status().Min)
+
+	assert.Equal(t, gl, c.Update(0, gl, fast, 0), "hold if nothing measured")
+	assert.Equal(t, gl, c.Update(gl, gl, fast, 0), "hold if no backlog")
+	assert.Equal(t, gl*4, c.Update(gl, gl, fast, 10), "grow to capacity")
+	assert.Equal(t, uint64(50*1000*1000), c.Update(gl*4, gl*4, fast, 10), "bounded by max")
+	assert.Equal(t, gl/2, c.Update(gl, gl, powerplay.TolerableBlockPackingTime, 10), "shrink if cut short by the budget")
+	assert.Equal(t, gl/5, c.Update(gl/4, gl, slow, 10), "shrink if too slow though within the budget")
+	assert.Equal(t, powerplay.MinGasLimit, c.Update(gl/10, gl/10, time.Hour, 0), "bounded by min")
+
+	s := c.Status()
+	assert.Equal(t, powerplay.MinGasLimit, s.Target)
+	assert.Equal(t, gl/10, s.GasUsed)
+	assert.Equal(t, time.Hour, s.Elapsed)
+}
@@ -25,6 +25,8 @@ type Packer struct {
 	nodeMaster     powerplay.Address
 	beneficiary    *powerplay.Address
 	targetGasLimit uint64
+	forkConfig     powerplay.ForkConfig
+	strategy       Strategy
 }
 
 // New create a new Packer instance.
@@ -41,6 +43,8 @@ func New(
 		nodeMaster,
 		beneficiary,
 		0,
+		powerplay.GetForkConfig(chain.GenesisBlock().Header().ID()),
+		PoolOrderStrategy{},
 	}
 }
 
@@ -52,9 +56,10 @@ func (p *Packer) Schedule(parent *block.Header, nowTimestamp uint64) (flow *Flow
 	}
 
 	var (
+		params      = poa.LoadParams(state, p.forkConfig, parent.Number()+1)
 		endorsement = builtin.Params.Native(state).Get(powerplay.KeyProposerEndorsement)
 		authority   = builtin.Authority.Native(state)
-		candidates  = authority.Candidates(endorsement, powerplay.MaxBlockProposers)
+		candidates  = authority.Candidates(endorsement, params.MaxBlockProposers)
 		proposers   = make([]poa.Proposer, 0, len(candidates))
 		beneficiary powerplay.Address
 	)
@@ -74,7 +79,7 @@ func (p *Packer) Schedule(parent *block.Header, nowTimestamp uint64) (flow *Flow
 	}
 
 	// calc the time when it's turn to produce block
-	sched, err := poa.NewScheduler(p.nodeMaster, proposers, parent.Number(), parent.Timestamp())
+	sched, err := poa.NewScheduler(p.nodeMaster, proposers, parent.Number(), parent.Timestamp(), params)
 	if err != nil {
 		return nil, err
 	}
@@ -137,3 +142,8 @@ func (p *Packer) gasLimit(parentGasLimit uint64) uint64 {
 func (p *Packer) SetTargetGasLimit(gl uint64) {
 	p.targetGasLimit = gl
 }
+
+// SetStrategy set the strategy to order pending txs, see Flow.AdoptPending.
+func (p *Packer) SetStrategy(strategy Strategy) {
+	p.strategy = strategy
+}
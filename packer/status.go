@@ -0,0 +1,116 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package packer
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/builtin"
+	"github.com/playmakerchain/powerplay/poa"
+	"github.com/playmakerchain/powerplay/powerplay"
+)
+
+// ProposerStatus describes whether the node master is able to produce blocks.
+type ProposerStatus struct {
+	Master          powerplay.Address
+	Listed          bool // listed in authority
+	Active          bool
+	Endorsor        powerplay.Address
+	EndorsorBalance *big.Int
+	Endorsement     *big.Int // required balance of endorsor
+	Candidate       bool     // endorsed and within max block proposers, i.e. scheduled to produce blocks
+	Slots           []uint64 // time of next slots
+}
+
+// DryRunResult the outcome of building a block without signing it.
+type DryRunResult struct {
+	Number    uint32
+	Timestamp uint64
+	GasLimit  uint64
+	GasUsed   uint64
+	TxCount   int
+	StateRoot powerplay.Bytes32
+	Elapsed   time.Duration
+}
+
+// Status checks the node master against authority upon the parent, and previews time of up to
+// `slots` next slots, assuming no other blocks produced meanwhile.
+func (p *Packer) Status(parent *block.Header, nowTimestamp uint64, slots int) (*ProposerStatus, error) {
+	state, err := p.stateCreator.NewState(parent.StateRoot())
+	if err != nil {
+		return nil, errors.Wrap(err, "state")
+	}
+
+	var (
+		params      = poa.LoadParams(state, p.forkConfig, parent.Number()+1)
+		endorsement = builtin.Params.Native(state).Get(powerplay.KeyProposerEndorsement)
+		authority   = builtin.Authority.Native(state)
+		candidates  = authority.Candidates(endorsement, params.MaxBlockProposers)
+		proposers   = make([]poa.Proposer, 0, len(candidates))
+		status      = &ProposerStatus{Master: p.nodeMaster, Endorsement: endorsement}
+	)
+
+	status.Listed, status.Endorsor, _, status.Active = authority.Get(p.nodeMaster)
+	if status.Listed {
+		status.EndorsorBalance = state.GetBalance(status.Endorsor)
+	}
+
+	for _, c := range candidates {
+		if c.NodeMaster == p.nodeMaster {
+			status.Candidate = true
+		}
+		proposers = append(proposers, poa.Proposer{
+			Address: c.NodeMaster,
+			Active:  c.Active,
+		})
+	}
+	if err := state.Err(); err != nil {
+		return nil, errors.Wrap(err, "state")
+	}
+
+	if status.Candidate && slots > 0 {
+		sched, err := poa.NewScheduler(p.nodeMaster, proposers, parent.Number(), parent.Timestamp(), params)
+		if err != nil {
+			return nil, err
+		}
+		t := sched.Schedule(nowTimestamp)
+		for len(status.Slots) < slots {
+			status.Slots = append(status.Slots, t)
+			t = sched.Schedule(t + 1)
+		}
+	}
+	return status, nil
+}
+
+// DryRun builds a block upon the parent by Mock, with pending txs adopted as Flow.AdoptPending does.
+// The block is never signed, so it's safe to check block production at any time.
+func (p *Packer) DryRun(parent *block.Header, targetTime uint64, gasLimit uint64, txs []*PendingTx) (*DryRunResult, error) {
+	startTime := time.Now()
+
+	flow, err := p.Mock(parent, targetTime, gasLimit)
+	if err != nil {
+		return nil, err
+	}
+	flow.AdoptPending(txs, powerplay.TolerableBlockPackingTime)
+
+	newBlock, _, err := flow.build()
+	if err != nil {
+		return nil, err
+	}
+	header := newBlock.Header()
+	return &DryRunResult{
+		Number:    header.Number(),
+		Timestamp: header.Timestamp(),
+		GasLimit:  header.GasLimit(),
+		GasUsed:   header.GasUsed(),
+		TxCount:   len(newBlock.Transactions()),
+		StateRoot: header.StateRoot(),
+		Elapsed:   time.Since(startTime),
+	}, nil
+}
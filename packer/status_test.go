@@ -0,0 +1,65 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package packer_test
+
+import (
+	"testing"
+
+	"github.com/playmakerchain/powerplay/genesis"
+	"github.com/playmakerchain/powerplay/packer"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/test/testchain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatus(t *testing.T) {
+	c := testchain.New(t)
+	defer c.KV.Close()
+	b0 := c.Genesis
+
+	a0 := genesis.DevAccounts()[0]
+	a1 := genesis.DevAccounts()[1]
+	parent := b0.Header()
+
+	status, err := packer.New(c.Chain, c.StateCreator, a0.Address, nil).Status(parent, parent.Timestamp(), 3)
+	assert.Nil(t, err)
+	assert.True(t, status.Listed)
+	assert.True(t, status.Candidate)
+	assert.Equal(t, a0.Address, status.Endorsor)
+	assert.True(t, status.EndorsorBalance.Cmp(status.Endorsement) >= 0)
+	assert.Equal(t, []uint64{
+		parent.Timestamp() + powerplay.BlockInterval,
+		parent.Timestamp() + powerplay.BlockInterval*2,
+		parent.Timestamp() + powerplay.BlockInterval*3,
+	}, status.Slots)
+
+	status, err = packer.New(c.Chain, c.StateCreator, a1.Address, nil).Status(parent, parent.Timestamp(), 3)
+	assert.Nil(t, err)
+	assert.False(t, status.Listed)
+	assert.False(t, status.Candidate)
+	assert.Empty(t, status.Slots)
+}
+
+func TestDryRun(t *testing.T) {
+	c := testchain.New(t)
+	defer c.KV.Close()
+	b0 := c.Genesis
+
+	a0 := genesis.DevAccounts()[0]
+	iter := &txIterator{chainTag: b0.Header().ID()[31]}
+	txs := []*packer.PendingTx{
+		{Transaction: iter.Next(), Origin: a0.Address},
+		{Transaction: iter.Next(), Origin: a0.Address},
+	}
+
+	p := packer.New(c.Chain, c.StateCreator, a0.Address, nil)
+	result, err := p.DryRun(b0.Header(), b0.Header().Timestamp()+powerplay.BlockInterval, b0.Header().GasLimit(), txs)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), result.Number)
+	assert.Equal(t, 2, result.TxCount)
+	assert.NotZero(t, result.GasUsed)
+	assert.Equal(t, b0.Header().ID(), c.BestBlock().Header().ID(), "nothing committed")
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package packer
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/tx"
+)
+
+// PendingTx a tx pending to be packed, along with its pool metadata.
+type PendingTx struct {
+	*tx.Transaction
+	Origin    powerplay.Address
+	TimeAdded int64    // unix nano time when the tx arrived the pool
+	GasPrice  *big.Int // overall gas price, filled by the flow if nil
+}
+
+// Strategy decides the order in which pending txs are adopted into the new block.
+type Strategy interface {
+	Order(txs []*PendingTx) []*PendingTx
+}
+
+// PoolOrderStrategy keeps the order given by tx pool.
+type PoolOrderStrategy struct{}
+
+// Order implements Strategy.
+func (PoolOrderStrategy) Order(txs []*PendingTx) []*PendingTx { return txs }
+
+// MaxFeeStrategy orders txs by overall gas price from high to low.
+type MaxFeeStrategy struct{}
+
+// Order implements Strategy.
+func (MaxFeeStrategy) Order(txs []*PendingTx) []*PendingTx {
+	sorted := append([]*PendingTx(nil), txs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].GasPrice.Cmp(sorted[j].GasPrice) > 0
+	})
+	return sorted
+}
+
+// FIFOStrategy orders txs by arrival time.
+type FIFOStrategy struct{}
+
+// Order implements Strategy.
+func (FIFOStrategy) Order(txs []*PendingTx) []*PendingTx {
+	sorted := append([]*PendingTx(nil), txs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].TimeAdded < sorted[j].TimeAdded
+	})
+	return sorted
+}
+
+// FairStrategy takes txs from each origin in turn, so that one origin can't fill blocks
+// while others are waiting. Txs of the same origin keep the order given by Next, which
+// defaults to pool order.
+type FairStrategy struct {
+	Next Strategy
+}
+
+// Order implements Strategy.
+func (s *FairStrategy) Order(txs []*PendingTx) []*PendingTx {
+	if s.Next != nil {
+		txs = s.Next.Order(txs)
+	}
+	var (
+		origins []powerplay.Address
+		queues  = make(map[powerplay.Address][]*PendingTx)
+	)
+	for _, ptx := range txs {
+		if _, ok := queues[ptx.Origin]; !ok {
+			origins = append(origins, ptx.Origin)
+		}
+		queues[ptx.Origin] = append(queues[ptx.Origin], ptx)
+	}
+
+	ordered := make([]*PendingTx, 0, len(txs))
+	for len(ordered) < len(txs) {
+		for _, origin := range origins {
+			if queue := queues[origin]; len(queue) > 0 {
+				ordered = append(ordered, queue[0])
+				queues[origin] = queue[1:]
+			}
+		}
+	}
+	return ordered
+}
+
+// PriorityStrategy puts txs from listed origins, or calling listed contracts, ahead of others.
+// Both groups are ordered by Next, which defaults to pool order.
+type PriorityStrategy struct {
+	Origins map[powerplay.Address]bool
+	To      map[powerplay.Address]bool
+	Next    Strategy
+}
+
+// Order implements Strategy.
+func (s *PriorityStrategy) Order(txs []*PendingTx) []*PendingTx {
+	if s.Next != nil {
+		txs = s.Next.Order(txs)
+	}
+	ordered := make([]*PendingTx, 0, len(txs))
+	var others []*PendingTx
+	for _, ptx := range txs {
+		if s.matches(ptx) {
+			ordered = append(ordered, ptx)
+		} else {
+			others = append(others, ptx)
+		}
+	}
+	return append(ordered, others...)
+}
+
+func (s *PriorityStrategy) matches(ptx *PendingTx) bool {
+	if s.Origins[ptx.Origin] {
+		return true
+	}
+	for _, clause := range ptx.Clauses() {
+		if to := clause.To(); to != nil && s.To[*to] {
+			return true
+		}
+	}
+	return false
+}
+
+// NewStrategy create strategy by name, which is one of pool, maxfee, fair and fifo.
+func NewStrategy(name string) (Strategy, error) {
+	switch name {
+	case "", "pool":
+		return PoolOrderStrategy{}, nil
+	case "maxfee":
+		return MaxFeeStrategy{}, nil
+	case "fair":
+		return &FairStrategy{}, nil
+	case "fifo":
+		return FIFOStrategy{}, nil
+	}
+	return nil, fmt.Errorf("unknown tx strategy '%v'", name)
+}
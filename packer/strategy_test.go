@@ -0,0 +1,88 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package packer_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/playmakerchain/powerplay/packer"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/tx"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	origin1  = powerplay.BytesToAddress([]byte("origin1"))
+	origin2  = powerplay.BytesToAddress([]byte("origin2"))
+	contract = powerplay.BytesToAddress([]byte("contract"))
+)
+
+func newPendingTx(n uint64, origin powerplay.Address, timeAdded int64, gasPrice int64) *packer.PendingTx {
+	return &packer.PendingTx{
+		Transaction: new(tx.Builder).Nonce(n).Build(),
+		Origin:      origin,
+		TimeAdded:   timeAdded,
+		GasPrice:    big.NewInt(gasPrice),
+	}
+}
+
+func nonces(txs []*packer.PendingTx) []uint64 {
+	var ns []uint64
+	for _, ptx := range txs {
+		ns = append(ns, ptx.Nonce())
+	}
+	return ns
+}
+
+func TestStrategy(t *testing.T) {
+	txs := []*packer.PendingTx{
+		newPendingTx(0, origin1, 3, 10),
+		newPendingTx(1, origin1, 1, 20),
+		newPendingTx(2, origin1, 2, 30),
+		newPendingTx(3, origin2, 5, 10),
+		newPendingTx(4, origin2, 4, 30),
+	}
+
+	tests := []struct {
+		name     string
+		strategy packer.Strategy
+		want     []uint64
+	}{
+		{"pool", packer.PoolOrderStrategy{}, []uint64{0, 1, 2, 3, 4}},
+		{"maxfee", packer.MaxFeeStrategy{}, []uint64{2, 4, 1, 0, 3}},
+		{"fifo", packer.FIFOStrategy{}, []uint64{1, 2, 0, 4, 3}},
+		{"fair", &packer.FairStrategy{}, []uint64{0, 3, 1, 4, 2}},
+		{"fair fifo", &packer.FairStrategy{Next: packer.FIFOStrategy{}}, []uint64{1, 4, 2, 3, 0}},
+		{"priority", &packer.PriorityStrategy{Origins: map[powerplay.Address]bool{origin2: true}}, []uint64{3, 4, 0, 1, 2}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, nonces(tt.strategy.Order(txs)), tt.name)
+	}
+	assert.Equal(t, []uint64{0, 1, 2, 3, 4}, nonces(txs), "input untouched")
+}
+
+func TestPriorityStrategyTo(t *testing.T) {
+	call := &packer.PendingTx{
+		Transaction: new(tx.Builder).Nonce(9).Clause(tx.NewClause(&contract)).Build(),
+		Origin:      origin2,
+		GasPrice:    big.NewInt(0),
+	}
+	txs := []*packer.PendingTx{newPendingTx(0, origin1, 0, 10), call}
+
+	s := &packer.PriorityStrategy{To: map[powerplay.Address]bool{contract: true}, Next: packer.MaxFeeStrategy{}}
+	assert.Equal(t, []uint64{9, 0}, nonces(s.Order(txs)))
+}
+
+func TestNewStrategy(t *testing.T) {
+	for _, name := range []string{"", "pool", "maxfee", "fair", "fifo"} {
+		s, err := packer.NewStrategy(name)
+		assert.Nil(t, err, name)
+		assert.NotNil(t, s, name)
+	}
+	_, err := packer.NewStrategy("unknown")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package poa
+
+import (
+	"math/big"
+
+	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/builtin"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/state"
+)
+
+// Params governable params of scheduling.
+type Params struct {
+	BlockInterval     uint64 // time interval between two consecutive blocks
+	MaxBlockProposers uint64 // max count of listed proposers
+}
+
+// DefaultParams params in effect before governed.
+var DefaultParams = Params{
+	BlockInterval:     powerplay.BlockInterval,
+	MaxBlockProposers: powerplay.MaxBlockProposers,
+}
+
+// LoadParams loads params to schedule the block of given number, from the state of its parent.
+// Values set via the `Params` builtin take effect since the GovernedSchedule fork,
+// and unset ones fall back to defaults.
+func LoadParams(st *state.State, forkConfig powerplay.ForkConfig, blockNum uint32) Params {
+	params := DefaultParams
+	if blockNum < forkConfig.GovernedSchedule {
+		return params
+	}
+
+	native := builtin.Params.Native(st)
+	if v := native.Get(powerplay.KeyBlockInterval); isValidParam(v) && v.Uint64() <= powerplay.MaxBlockInterval {
+		params.BlockInterval = v.Uint64()
+	}
+	if v := native.Get(powerplay.KeyMaxBlockProposers); isValidParam(v) {
+		params.MaxBlockProposers = v.Uint64()
+	}
+	return params
+}
+
+// LoadParamsAfter loads params to schedule the block after the parent block, from the parent's state.
+func LoadParamsAfter(stateCreator *state.Creator, forkConfig powerplay.ForkConfig, parent *block.Header) (Params, error) {
+	st, err := stateCreator.NewState(parent.StateRoot())
+	if err != nil {
+		return Params{}, err
+	}
+	params := LoadParams(st, forkConfig, parent.Number()+1)
+	if err := st.Err(); err != nil {
+		return Params{}, err
+	}
+	return params, nil
+}
+
+func isValidParam(v *big.Int) bool {
+	return v != nil && v.Sign() > 0 && v.IsUint64()
+}
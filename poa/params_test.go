@@ -0,0 +1,38 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package poa_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/playmakerchain/powerplay/builtin"
+	"github.com/playmakerchain/powerplay/lvldb"
+	"github.com/playmakerchain/powerplay/poa"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadParams(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	st, _ := state.New(powerplay.Bytes32{}, kv)
+	fc := powerplay.ForkConfig{GovernedSchedule: 10}
+
+	assert.Equal(t, poa.DefaultParams, poa.LoadParams(st, fc, 10), "unset")
+
+	builtin.Params.Native(st).Set(powerplay.KeyBlockInterval, big.NewInt(3))
+	assert.Equal(t, poa.DefaultParams, poa.LoadParams(st, fc, 9), "before fork")
+	assert.Equal(t, poa.Params{BlockInterval: 3, MaxBlockProposers: powerplay.MaxBlockProposers}, poa.LoadParams(st, fc, 10))
+
+	builtin.Params.Native(st).Set(powerplay.KeyMaxBlockProposers, big.NewInt(21))
+	assert.Equal(t, poa.Params{BlockInterval: 3, MaxBlockProposers: 21}, poa.LoadParams(st, fc, 10))
+
+	builtin.Params.Native(st).Set(powerplay.KeyBlockInterval, new(big.Int).SetUint64(powerplay.MaxBlockInterval+1))
+	assert.Equal(t, powerplay.BlockInterval, poa.LoadParams(st, fc, 10).BlockInterval, "too large")
+	builtin.Params.Native(st).Set(powerplay.KeyBlockInterval, new(big.Int).SetUint64(powerplay.MaxBlockInterval))
+	assert.Equal(t, powerplay.MaxBlockInterval, poa.LoadParams(st, fc, 10).BlockInterval)
+}
@@ -19,16 +19,19 @@ type Scheduler struct {
 	actives           []Proposer
 	parentBlockNumber uint32
 	parentBlockTime   uint64
+	params            Params
 }
 
 // NewScheduler create a Scheduler object.
 // `addr` is the proposer to be scheduled.
 // If `addr` is not listed in `proposers`, an error returned.
+// `params` is usually loaded by LoadParams.
 func NewScheduler(
 	addr powerplay.Address,
 	proposers []Proposer,
 	parentBlockNumber uint32,
-	parentBlockTime uint64) (*Scheduler, error) {
+	parentBlockTime uint64,
+	params Params) (*Scheduler, error) {
 
 	actives := make([]Proposer, 0, len(proposers))
 	listed := false
@@ -52,6 +55,7 @@ func NewScheduler(
 		actives,
 		parentBlockNumber,
 		parentBlockTime,
+		params,
 	}, nil
 }
 
@@ -63,7 +67,7 @@ func (s *Scheduler) whoseTurn(t uint64) Proposer {
 // Schedule to determine time of the proposer to produce a block, according to `nowTime`.
 // `newBlockTime` is promised to be >= nowTime and > parentBlockTime
 func (s *Scheduler) Schedule(nowTime uint64) (newBlockTime uint64) {
-	const T = powerplay.BlockInterval
+	T := s.params.BlockInterval
 
 	newBlockTime = s.parentBlockTime + T
 
@@ -90,7 +94,7 @@ func (s *Scheduler) IsTheTime(newBlockTime uint64) bool {
 		return false
 	}
 
-	if (newBlockTime-s.parentBlockTime)%powerplay.BlockInterval != 0 {
+	if (newBlockTime-s.parentBlockTime)%s.params.BlockInterval != 0 {
 		// invalid block time
 		return false
 	}
@@ -103,13 +107,13 @@ func (s *Scheduler) Updates(newBlockTime uint64) (updates []Proposer, score uint
 
 	toDeactivate := make(map[powerplay.Address]Proposer)
 
-	t := newBlockTime - powerplay.BlockInterval
-	for i := uint64(0); i < powerplay.MaxBlockProposers && t > s.parentBlockTime; i++ {
+	t := newBlockTime - s.params.BlockInterval
+	for i := uint64(0); i < s.params.MaxBlockProposers && t > s.parentBlockTime; i++ {
 		p := s.whoseTurn(t)
 		if p.Address != s.proposer.Address {
 			toDeactivate[p.Address] = p
 		}
-		t -= powerplay.BlockInterval
+		t -= s.params.BlockInterval
 	}
 
 	updates = make([]Proposer, 0, len(toDeactivate)+1)
@@ -128,6 +132,22 @@ func (s *Scheduler) Updates(newBlockTime uint64) (updates []Proposer, score uint
 	return
 }
 
+// Missed returns proposers of the time slots skipped before new block time, in time order.
+// Like Updates, at most MaxBlockProposers slots are taken into account.
+func (s *Scheduler) Missed(newBlockTime uint64) []Proposer {
+	var missed []Proposer
+	t := newBlockTime - s.params.BlockInterval
+	for i := uint64(0); i < s.params.MaxBlockProposers && t > s.parentBlockTime; i++ {
+		missed = append(missed, s.whoseTurn(t))
+		t -= s.params.BlockInterval
+	}
+	// reverse into time order
+	for i, j := 0, len(missed)-1; i < j; i, j = i+1, j-1 {
+		missed[i], missed[j] = missed[j], missed[i]
+	}
+	return missed
+}
+
 // dprp deterministic pseudo-random process.
 // H(B, t)[:8]
 func dprp(blockNumber uint32, time uint64) uint64 {
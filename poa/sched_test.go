@@ -34,10 +34,10 @@ var (
 
 func TestSchedule(t *testing.T) {
 
-	_, err := poa.NewScheduler(powerplay.BytesToAddress([]byte("px")), proposers, 1, parentTime)
+	_, err := poa.NewScheduler(powerplay.BytesToAddress([]byte("px")), proposers, 1, parentTime, poa.DefaultParams)
 	assert.NotNil(t, err)
 
-	sched, _ := poa.NewScheduler(p1, proposers, 1, parentTime)
+	sched, _ := poa.NewScheduler(p1, proposers, 1, parentTime, poa.DefaultParams)
 
 	for i := uint64(0); i < 100; i++ {
 		now := parentTime + i*powerplay.BlockInterval/2
@@ -48,7 +48,7 @@ func TestSchedule(t *testing.T) {
 }
 
 func TestIsTheTime(t *testing.T) {
-	sched, _ := poa.NewScheduler(p2, proposers, 1, parentTime)
+	sched, _ := poa.NewScheduler(p2, proposers, 1, parentTime, poa.DefaultParams)
 
 	tests := []struct {
 		now  uint64
@@ -66,7 +66,7 @@ func TestIsTheTime(t *testing.T) {
 
 func TestUpdates(t *testing.T) {
 
-	sched, _ := poa.NewScheduler(p1, proposers, 1, parentTime)
+	sched, _ := poa.NewScheduler(p1, proposers, 1, parentTime, poa.DefaultParams)
 
 	tests := []struct {
 		newBlockTime uint64
@@ -81,3 +81,32 @@ func TestUpdates(t *testing.T) {
 		assert.Equal(t, tt.want, score)
 	}
 }
+
+func TestMissed(t *testing.T) {
+	sched, _ := poa.NewScheduler(p1, proposers, 1, parentTime, poa.DefaultParams)
+
+	assert.Empty(t, sched.Missed(parentTime+powerplay.BlockInterval))
+
+	// p1 and p2 are the actives
+	missed := sched.Missed(parentTime + powerplay.BlockInterval*5)
+	assert.Equal(t, 4, len(missed))
+	for i, p := range missed {
+		slotTime := parentTime + powerplay.BlockInterval*uint64(i+1)
+		assert.Equal(t, p.Address == p1, sched.IsTheTime(slotTime))
+	}
+}
+
+func TestParams(t *testing.T) {
+	params := poa.Params{BlockInterval: 3, MaxBlockProposers: 2}
+	sched, _ := poa.NewScheduler(p1, proposers, 1, parentTime, params)
+
+	for i := uint64(0); i < 100; i++ {
+		nbt := sched.Schedule(parentTime + i)
+		assert.Equal(t, uint64(0), (nbt-parentTime)%params.BlockInterval)
+		assert.True(t, sched.IsTheTime(nbt))
+	}
+	assert.False(t, sched.IsTheTime(parentTime+powerplay.BlockInterval))
+
+	// at most MaxBlockProposers slots taken into account
+	assert.Equal(t, 2, len(sched.Missed(parentTime+params.BlockInterval*10)))
+}
@@ -7,37 +7,71 @@
 package powerplay
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
+	"sync"
 )
 
 // ForkConfig config for a fork.
 type ForkConfig struct {
-	FixTransferLog uint32
+	FixTransferLog   uint32 `json:"fixTransferLog"`
+	FeeDelegation    uint32 `json:"feeDelegation"`    // designated gas payer via tx features
+	GovernedSchedule uint32 `json:"governedSchedule"` // block interval and max block proposers governed via params
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// Forks omitted are never activated, rather than activated at genesis.
+func (fc *ForkConfig) UnmarshalJSON(data []byte) error {
+	type plain ForkConfig
+	v := plain(NoFork)
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*fc = ForkConfig(v)
+	return nil
 }
 
 func (fc ForkConfig) String() string {
-	return fmt.Sprintf("FTRL: #%v", fc.FixTransferLog)
+	return fmt.Sprintf("FTRL: #%v, FDEL: #%v, GSCH: #%v", fc.FixTransferLog, fc.FeeDelegation, fc.GovernedSchedule)
 }
 
 // NoFork a special config without any forks.
 var NoFork = ForkConfig{
-	FixTransferLog: math.MaxUint32,
+	FixTransferLog:   math.MaxUint32,
+	FeeDelegation:    math.MaxUint32,
+	GovernedSchedule: math.MaxUint32,
 }
 
+var forkConfigsLock sync.RWMutex
+
 // for well-known networks
 var forkConfigs = map[Bytes32]ForkConfig{
 	// mainnet
 	MustParseBytes32("0x00000000851caf3cfdb6e899cf5958bfb1ac3413d346d43539627e6be7ec1b4a"): {
-		FixTransferLog: 1072000,
+		FixTransferLog:   1072000,
+		FeeDelegation:    math.MaxUint32,
+		GovernedSchedule: math.MaxUint32,
 	},
 	// testnet
 	MustParseBytes32("0x000000000b2bce3c70bc649a02749e8687721b09ed2e15997f466536b20bb127"): {
-		FixTransferLog: 1080000,
+		FixTransferLog:   1080000,
+		FeeDelegation:    math.MaxUint32,
+		GovernedSchedule: math.MaxUint32,
 	},
 }
 
 // GetForkConfig get fork config for given genesis ID.
 func GetForkConfig(genesisID Bytes32) ForkConfig {
+	forkConfigsLock.RLock()
+	defer forkConfigsLock.RUnlock()
 	return forkConfigs[genesisID]
 }
+
+// RegisterForkConfig register fork config for a custom network.
+// It should be called before any component of the network is created.
+func RegisterForkConfig(genesisID Bytes32, fc ForkConfig) {
+	forkConfigsLock.Lock()
+	defer forkConfigsLock.Unlock()
+	forkConfigs[genesisID] = fc
+}
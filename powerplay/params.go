@@ -15,7 +15,8 @@ import (
 
 // Constants of block chain.
 const (
-	BlockInterval uint64 = 10 // time interval between two consecutive blocks.
+	BlockInterval    uint64 = 10   // default time interval between two consecutive blocks, governed by KeyBlockInterval.
+	MaxBlockInterval uint64 = 3600 // upper bound of governed block interval, beyond which the governed value is ignored.
 
 	TxGas                     uint64 = 5000
 	ClauseGas                 uint64 = params.TxGas - TxGas
@@ -31,7 +32,7 @@ const (
 
 	MaxTxWorkDelay uint32 = 30 // (unit: block) if tx delay exceeds this value, no energy can be exchanged.
 
-	MaxBlockProposers uint64 = 101
+	MaxBlockProposers uint64 = 101 // default max count of listed proposers, governed by KeyMaxBlockProposers.
 
 	TolerableBlockPackingTime = 2 * time.Second // the indicator to adjust target block gas limit
 
@@ -44,6 +45,8 @@ var (
 	KeyRewardRatio         = BytesToBytes32([]byte("reward-ratio"))
 	KeyBaseGasPrice        = BytesToBytes32([]byte("base-gas-price"))
 	KeyProposerEndorsement = BytesToBytes32([]byte("proposer-endorsement"))
+	KeyBlockInterval       = BytesToBytes32([]byte("block-interval"))
+	KeyMaxBlockProposers   = BytesToBytes32([]byte("max-block-proposers"))
 
 	InitialRewardRatio         = big.NewInt(3e17) // 30%
 	InitialBaseGasPrice        = big.NewInt(1e15)
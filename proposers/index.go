@@ -0,0 +1,244 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package proposers
+
+import (
+	"context"
+	"encoding/binary"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/builtin"
+	"github.com/playmakerchain/powerplay/chain"
+	"github.com/playmakerchain/powerplay/kv"
+	"github.com/playmakerchain/powerplay/poa"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/state"
+)
+
+var (
+	log = log15.New("pkg", "proposers")
+
+	headKey      = []byte("head")
+	recordPrefix = []byte("r") // (prefix, block num) -> record
+)
+
+// Record the result of replaying poa scheduling for a trunk block.
+type Record struct {
+	BlockID   powerplay.Bytes32
+	Timestamp uint64
+	Signer    powerplay.Address
+	Missed    []powerplay.Address // proposers of skipped slots before the block, in time order
+	Updates   []poa.Proposer      // proposers whose active status changed by the block
+}
+
+// Index records proposer performance of trunk blocks, by replaying poa scheduling against trunk headers.
+type Index struct {
+	kv           kv.GetPutter
+	chain        *chain.Chain
+	stateCreator *state.Creator
+	forkConfig   powerplay.ForkConfig
+}
+
+// New create a proposer index persisted in kv.
+func New(kv kv.GetPutter, chain *chain.Chain, stateCreator *state.Creator) *Index {
+	return &Index{
+		kv,
+		chain,
+		stateCreator,
+		powerplay.GetForkConfig(chain.GenesisBlock().Header().ID()),
+	}
+}
+
+// HeadID returns ID of the last indexed block.
+// ok is false if nothing indexed.
+func (x *Index) HeadID() (id powerplay.Bytes32, ok bool, err error) {
+	data, err := x.kv.Get(headKey)
+	if err != nil {
+		if x.kv.IsNotFound(err) {
+			return powerplay.Bytes32{}, false, nil
+		}
+		return powerplay.Bytes32{}, false, err
+	}
+	return powerplay.BytesToBytes32(data), true, nil
+}
+
+// GetRecord returns the record of indexed block at num.
+func (x *Index) GetRecord(num uint32) (*Record, error) {
+	data, err := x.kv.Get(recordKey(num))
+	if err != nil {
+		return nil, err
+	}
+	var rec Record
+	if err := rlp.DecodeBytes(data, &rec); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+// IsNotFound returns whether the error indicates block not indexed.
+func (x *Index) IsNotFound(err error) bool {
+	return x.kv.IsNotFound(err)
+}
+
+// Sync reverts records not on trunk, and indexes trunk blocks up to the best one.
+func (x *Index) Sync(ctx context.Context) error {
+	best := x.chain.BestBlock().Header()
+
+	// genesis is never indexed
+	next := uint32(1)
+	headID, ok, err := x.HeadID()
+	if err != nil {
+		return err
+	}
+	if ok {
+		for num := block.Number(headID); num > 0; num-- {
+			if num <= best.Number() {
+				rec, err := x.GetRecord(num)
+				if err != nil {
+					return err
+				}
+				trunkID, err := x.chain.GetAncestorBlockID(best.ID(), num)
+				if err != nil {
+					return err
+				}
+				if trunkID == rec.BlockID {
+					next = num + 1
+					break
+				}
+			}
+			if err := x.revert(num); err != nil {
+				return err
+			}
+		}
+	}
+
+	startTime := time.Now()
+	for num := next; num <= best.Number(); num++ {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+		id, err := x.chain.GetAncestorBlockID(best.ID(), num)
+		if err != nil {
+			return err
+		}
+		rec, err := x.replay(id)
+		if err != nil {
+			return errors.WithMessage(err, "replay")
+		}
+		if err := x.put(num, rec); err != nil {
+			return err
+		}
+
+		if time.Since(startTime) > 10*time.Second {
+			log.Info("indexing proposers", "number", num, "best", best.Number())
+			startTime = time.Now()
+		}
+	}
+	return nil
+}
+
+// replay replays poa scheduling for the block.
+func (x *Index) replay(id powerplay.Bytes32) (*Record, error) {
+	header, err := x.chain.GetBlockHeader(id)
+	if err != nil {
+		return nil, err
+	}
+	parent, err := x.chain.GetBlockHeader(header.ParentID())
+	if err != nil {
+		return nil, err
+	}
+	signer, err := header.Signer()
+	if err != nil {
+		return nil, err
+	}
+	st, err := x.stateCreator.NewState(parent.StateRoot())
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		params      = poa.LoadParams(st, x.forkConfig, header.Number())
+		endorsement = builtin.Params.Native(st).Get(powerplay.KeyProposerEndorsement)
+		candidates  = builtin.Authority.Native(st).Candidates(endorsement, params.MaxBlockProposers)
+		proposers   = make([]poa.Proposer, 0, len(candidates))
+	)
+	for _, c := range candidates {
+		proposers = append(proposers, poa.Proposer{
+			Address: c.NodeMaster,
+			Active:  c.Active,
+		})
+	}
+	if err := st.Err(); err != nil {
+		return nil, err
+	}
+
+	sched, err := poa.NewScheduler(signer, proposers, parent.Number(), parent.Timestamp(), params)
+	if err != nil {
+		return nil, err
+	}
+	missed := sched.Missed(header.Timestamp())
+	updates, _ := sched.Updates(header.Timestamp())
+
+	rec := &Record{
+		BlockID:   id,
+		Timestamp: header.Timestamp(),
+		Signer:    signer,
+		Missed:    make([]powerplay.Address, 0, len(missed)),
+		Updates:   updates,
+	}
+	for _, p := range missed {
+		rec.Missed = append(rec.Missed, p.Address)
+	}
+	return rec, nil
+}
+
+func (x *Index) put(num uint32, rec *Record) error {
+	data, err := rlp.EncodeToBytes(rec)
+	if err != nil {
+		return err
+	}
+	batch := x.kv.NewBatch()
+	if err := batch.Put(recordKey(num), data); err != nil {
+		return err
+	}
+	if err := batch.Put(headKey, rec.BlockID[:]); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// revert removes the record at num, which must be the head.
+func (x *Index) revert(num uint32) error {
+	batch := x.kv.NewBatch()
+	if err := batch.Delete(recordKey(num)); err != nil {
+		return err
+	}
+	if num > 1 {
+		rec, err := x.GetRecord(num - 1)
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(headKey, rec.BlockID[:]); err != nil {
+			return err
+		}
+	} else if err := batch.Delete(headKey); err != nil {
+		return err
+	}
+	log.Debug("proposer record reverted", "number", num)
+	return batch.Write()
+}
+
+func recordKey(num uint32) []byte {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], num)
+	return append(append([]byte(nil), recordPrefix...), b[:]...)
+}
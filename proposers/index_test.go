@@ -0,0 +1,70 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package proposers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/playmakerchain/powerplay/genesis"
+	"github.com/playmakerchain/powerplay/lvldb"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/test/testchain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndex(t *testing.T) {
+	c := testchain.New(t)
+	b0 := c.Genesis
+
+	db, _ := lvldb.NewMem()
+	x := New(db, c.Chain, c.StateCreator)
+
+	// the only proposer of devnet
+	master := genesis.DevAccounts()[0].Address
+
+	b1 := c.PackBlock(t, b0.Header(), 3)
+	b2 := c.PackBlock(t, b1.Header(), 1)
+	assert.Nil(t, x.Sync(context.Background()))
+
+	headID, ok, err := x.HeadID()
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, b2.Header().ID(), headID)
+
+	rec, err := x.GetRecord(1)
+	assert.Nil(t, err)
+	assert.Equal(t, master, rec.Signer)
+	assert.Equal(t, []powerplay.Address{master, master}, rec.Missed)
+
+	stats, err := x.Stats(0, b2.Header().Timestamp())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(stats))
+	assert.Equal(t, uint64(2), stats[0].Produced)
+	assert.Equal(t, uint64(2), stats[0].Missed)
+
+	stats, err = x.Stats(b2.Header().Timestamp(), b2.Header().Timestamp())
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), stats[0].Produced)
+	assert.Equal(t, uint64(0), stats[0].Missed)
+
+	// reorg by a longer branch
+	b1x := c.PackBlock(t, b0.Header(), 1)
+	b2x := c.PackBlock(t, b1x.Header(), 1)
+	b3x := c.PackBlock(t, b2x.Header(), 1)
+	assert.Equal(t, b3x.Header().ID(), c.BestBlock().Header().ID())
+	assert.Nil(t, x.Sync(context.Background()))
+
+	rec, err = x.GetRecord(1)
+	assert.Nil(t, err)
+	assert.Equal(t, b1x.Header().ID(), rec.BlockID)
+	assert.Empty(t, rec.Missed)
+
+	stats, err = x.Stats(0, b3x.Header().Timestamp())
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), stats[0].Produced)
+	assert.Equal(t, uint64(0), stats[0].Missed)
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package proposers
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/powerplay"
+)
+
+// Transition active status change of a proposer.
+type Transition struct {
+	Number    uint32
+	Timestamp uint64
+	Active    bool
+}
+
+// Stat performance of a proposer in a time window.
+type Stat struct {
+	Master      powerplay.Address
+	Produced    uint64
+	Missed      uint64
+	Transitions []Transition
+}
+
+// Stats returns performance of proposers within the time window [from, to], sorted by address.
+// Only proposers produced, missed or transited in the window are present.
+func (x *Index) Stats(from, to uint64) ([]*Stat, error) {
+	headID, ok, err := x.HeadID()
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	// records are in timestamp order, search for the first one in window
+	var searchErr error
+	head := block.Number(headID)
+	start := uint32(sort.Search(int(head), func(i int) bool {
+		rec, err := x.GetRecord(uint32(i) + 1)
+		if err != nil {
+			searchErr = err
+			return true
+		}
+		return rec.Timestamp >= from
+	})) + 1
+	if searchErr != nil {
+		return nil, searchErr
+	}
+
+	stats := make(map[powerplay.Address]*Stat)
+	get := func(addr powerplay.Address) *Stat {
+		s, ok := stats[addr]
+		if !ok {
+			s = &Stat{Master: addr}
+			stats[addr] = s
+		}
+		return s
+	}
+	for num := start; num <= head; num++ {
+		rec, err := x.GetRecord(num)
+		if err != nil {
+			return nil, err
+		}
+		if rec.Timestamp > to {
+			break
+		}
+		get(rec.Signer).Produced++
+		for _, addr := range rec.Missed {
+			get(addr).Missed++
+		}
+		for _, u := range rec.Updates {
+			s := get(u.Address)
+			s.Transitions = append(s.Transitions, Transition{num, rec.Timestamp, u.Active})
+		}
+	}
+
+	result := make([]*Stat, 0, len(stats))
+	for _, s := range stats {
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return bytes.Compare(result[i].Master[:], result[j].Master[:]) < 0
+	})
+	return result, nil
+}
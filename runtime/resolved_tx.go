@@ -22,6 +22,7 @@ import (
 type ResolvedTransaction struct {
 	tx           *tx.Transaction
 	Origin       powerplay.Address
+	Delegator    *powerplay.Address
 	IntrinsicGas uint64
 	Clauses      []*tx.Clause
 }
@@ -32,6 +33,10 @@ func ResolveTransaction(tx *tx.Transaction) (*ResolvedTransaction, error) {
 	if err != nil {
 		return nil, err
 	}
+	delegator, err := tx.Delegator()
+	if err != nil {
+		return nil, err
+	}
 	intrinsicGas, err := tx.IntrinsicGas()
 	if err != nil {
 		return nil, err
@@ -57,6 +62,7 @@ func ResolveTransaction(tx *tx.Transaction) (*ResolvedTransaction, error) {
 	return &ResolvedTransaction{
 		tx,
 		origin,
+		delegator,
 		intrinsicGas,
 		clauses,
 	}, nil
@@ -104,6 +110,14 @@ func (r *ResolvedTransaction) BuyGas(state *state.State, blockTime uint64) (
 	}
 
 	prepaid := new(big.Int).Mul(new(big.Int).SetUint64(r.tx.Gas()), gasPrice)
+	if r.Delegator != nil {
+		// the designated gas payer takes precedence
+		if energy.Sub(*r.Delegator, prepaid) {
+			return baseGasPrice, gasPrice, *r.Delegator, func(rgas uint64) { doReturnGas(rgas) }, nil
+		}
+		return nil, nil, powerplay.Address{}, nil, errors.New("insufficient energy")
+	}
+
 	commonTo := r.CommonTo()
 	if commonTo != nil {
 		binding := builtin.Prototype.Native(state).Bind(*commonTo)
@@ -116,6 +116,8 @@ func (rt *Runtime) SetVMConfig(config vm.Config) *Runtime {
 
 func (rt *Runtime) newEVM(stateDB *statedb.StateDB, clauseIndex uint32, txCtx *xenv.TransactionContext) *vm.EVM {
 	var lastNonNativeCallGas uint64
+	vmConfig := rt.vmConfig
+	vmConfig.EnablePreimageRecording = vmConfig.EnablePreimageRecording || rt.state.IsRecordingPreimages()
 	return vm.NewEVM(vm.Context{
 		CanTransfer: func(_ vm.StateDB, addr common.Address, amount *big.Int) bool {
 			return stateDB.GetBalance(addr).Cmp(amount) >= 0
@@ -252,7 +254,7 @@ func (rt *Runtime) newEVM(stateDB *statedb.StateDB, clauseIndex uint32, txCtx *x
 		BlockNumber: new(big.Int).SetUint64(uint64(rt.ctx.Number)),
 		Time:        new(big.Int).SetUint64(rt.ctx.Time),
 		Difficulty:  &big.Int{},
-	}, stateDB, &chainConfig, rt.vmConfig)
+	}, stateDB, &chainConfig, vmConfig)
 }
 
 // ExecuteClause executes single clause.
@@ -330,6 +332,9 @@ func (rt *Runtime) ExecuteTransaction(tx *tx.Transaction) (receipt *tx.Receipt,
 
 // PrepareTransaction prepare to execute tx.
 func (rt *Runtime) PrepareTransaction(tx *tx.Transaction) (*TransactionExecutor, error) {
+	if tx.Features() != 0 && rt.ctx.Number < rt.forkConfig.FeeDelegation {
+		return nil, errors.New("tx features not activated")
+	}
 	resolvedTx, err := ResolveTransaction(tx)
 	if err != nil {
 		return nil, err
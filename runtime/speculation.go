@@ -0,0 +1,96 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package runtime
+
+import (
+	goruntime "runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/playmakerchain/powerplay/state"
+	"github.com/playmakerchain/powerplay/tx"
+)
+
+// Speculation the result of a tx executed speculatively, to be committed by CommitSpeculation.
+type Speculation struct {
+	tx      *tx.Transaction
+	state   *state.State
+	receipt *tx.Receipt
+	err     error
+}
+
+// Speculate executes txs concurrently, each upon a speculation of current state.
+// The runtime must not be used until it returns, and speculations should be committed in order.
+func (rt *Runtime) Speculate(txs tx.Transactions) []*Speculation {
+	specs := make([]*Speculation, len(txs))
+	if rt.vmConfig.Tracer != nil || rt.seeker == nil {
+		// not safe to be executed concurrently
+		for i, tx := range txs {
+			specs[i] = &Speculation{tx: tx}
+		}
+		return specs
+	}
+
+	workers := goruntime.NumCPU()
+	if workers > len(txs) {
+		workers = len(txs)
+	}
+	var (
+		wg   sync.WaitGroup
+		next int32 = -1
+	)
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt32(&next, 1))
+				if i >= len(txs) {
+					return
+				}
+				specs[i] = rt.speculate(txs[i])
+			}
+		}()
+	}
+	wg.Wait()
+	return specs
+}
+
+func (rt *Runtime) speculate(tx *tx.Transaction) *Speculation {
+	specRT := &Runtime{
+		vmConfig:   rt.vmConfig,
+		seeker:     rt.seeker.Copy(),
+		state:      rt.state.NewSpeculation(),
+		ctx:        rt.ctx,
+		forkConfig: rt.forkConfig,
+	}
+	receipt, err := specRT.ExecuteTransaction(tx)
+	if specRT.seeker.Err() != nil {
+		// let it be executed again to report the error
+		return &Speculation{tx: tx}
+	}
+	return &Speculation{tx, specRT.state, receipt, err}
+}
+
+// CommitSpeculation commits the result of the speculatively executed tx, which is identical to ExecuteTransaction.
+// The tx is executed again, if values it read have been changed since speculated.
+func (rt *Runtime) CommitSpeculation(spec *Speculation) (*tx.Receipt, error) {
+	if spec.state != nil {
+		if spec.err != nil {
+			if rt.state.Validate(spec.state) {
+				return nil, spec.err
+			}
+		} else if rt.state.Merge(spec.state) {
+			return spec.receipt, nil
+		}
+	}
+	return rt.ExecuteTransaction(spec.tx)
+}
+
+// Tx returns the tx of the speculation.
+func (s *Speculation) Tx() *tx.Transaction {
+	return s.tx
+}
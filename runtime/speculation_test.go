@@ -0,0 +1,129 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package runtime_test
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/playmakerchain/powerplay/builtin"
+	"github.com/playmakerchain/powerplay/genesis"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/runtime"
+	"github.com/playmakerchain/powerplay/test/testchain"
+	"github.com/playmakerchain/powerplay/tx"
+	"github.com/playmakerchain/powerplay/xenv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpeculateEquivalence(t *testing.T) {
+	c := testchain.New(t)
+	accs := genesis.DevAccounts()
+
+	newTx := func(from genesis.DevAccount, nonce uint64, clause *tx.Clause) *tx.Transaction {
+		trx := new(tx.Builder).
+			ChainTag(c.Tag()).
+			Clause(clause).
+			Gas(300000).GasPriceCoef(0).Nonce(nonce).Expiration(math.MaxUint32).Build()
+		sig, _ := crypto.Sign(trx.SigningHash().Bytes(), from.PrivateKey)
+		return trx.WithSignature(sig)
+	}
+	transferEnergy := func(to powerplay.Address, amount *big.Int) *tx.Clause {
+		method, _ := builtin.Energy.ABI.MethodByName("transfer")
+		data, _ := method.EncodeInput(to, amount)
+		return tx.NewClause(&builtin.Energy.Address).WithData(data)
+	}
+	recipient := powerplay.BytesToAddress([]byte("recipient"))
+	key, _ := crypto.GenerateKey()
+	stranger := genesis.DevAccount{Address: powerplay.Address(crypto.PubkeyToAddress(key.PublicKey)), PrivateKey: key}
+	tooMuch := new(big.Int).Mul(big.NewInt(math.MaxInt64), big.NewInt(math.MaxInt64))
+
+	txs := tx.Transactions{
+		// same sender and recipient, conflicting
+		newTx(accs[1], 1, tx.NewClause(&recipient).WithValue(big.NewInt(1))),
+		newTx(accs[1], 2, tx.NewClause(&recipient).WithValue(big.NewInt(2))),
+		// unrelated to others
+		newTx(accs[2], 1, tx.NewClause(&accs[3].Address).WithValue(big.NewInt(3))),
+		newTx(accs[4], 1, transferEnergy(accs[5].Address, big.NewInt(4))),
+		// reads energy changed by the previous tx
+		newTx(accs[5], 1, transferEnergy(accs[6].Address, big.NewInt(5))),
+		// reverted
+		newTx(accs[7], 1, tx.NewClause(&recipient).WithValue(tooMuch)),
+		// rejected, since a fresh account can't pay for gas
+		newTx(stranger, 1, tx.NewClause(&recipient)),
+	}
+
+	parent := c.Genesis.Header()
+	newRuntime := func() *runtime.Runtime {
+		st, err := c.StateCreator.NewState(parent.StateRoot())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return runtime.New(c.NewSeeker(parent.ID()), st, &xenv.BlockContext{
+			Beneficiary: accs[8].Address,
+			Signer:      accs[0].Address,
+			Number:      parent.Number() + 1,
+			Time:        parent.Timestamp() + powerplay.BlockInterval,
+			GasLimit:    parent.GasLimit(),
+			TotalScore:  parent.TotalScore() + 1,
+		})
+	}
+
+	seqRT := newRuntime()
+	var seq result
+	for _, trx := range txs {
+		receipt, err := seqRT.ExecuteTransaction(trx)
+		seq.add(receipt, err)
+	}
+
+	specRT := newRuntime()
+	var spec result
+	for _, s := range specRT.Speculate(txs) {
+		spec.add(specRT.CommitSpeculation(s))
+	}
+
+	assert.Nil(t, seq.errs[0])
+	assert.Len(t, seq.receipts, 6)
+	assert.True(t, seq.receipts[5].Reverted, "should be reverted")
+	assert.NotNil(t, seq.errs[6], "should be rejected")
+
+	// errors carry stack traces, so compare messages only
+	assert.Equal(t, len(seq.errs), len(spec.errs))
+	for i, err := range seq.errs {
+		if err == nil {
+			assert.Nil(t, spec.errs[i])
+		} else {
+			assert.EqualError(t, spec.errs[i], err.Error())
+		}
+	}
+	assert.Equal(t, seq.receipts, spec.receipts)
+	assert.Equal(t, seq.receipts.RootHash(), spec.receipts.RootHash())
+
+	seqRoot, err := seqRT.State().Stage().Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specRoot, err := specRT.State().Stage().Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, seqRoot, specRoot)
+}
+
+// result collects results of txs executed one by one.
+type result struct {
+	receipts tx.Receipts
+	errs     []error
+}
+
+func (r *result) add(receipt *tx.Receipt, err error) {
+	if err == nil {
+		r.receipts = append(r.receipts, receipt)
+	}
+	r.errs = append(r.errs, err)
+}
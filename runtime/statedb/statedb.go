@@ -30,7 +30,6 @@ type StateDB struct {
 type (
 	suicideFlagKey common.Address
 	refundKey      struct{}
-	preimageKey    common.Hash
 	eventKey       struct{}
 	transferKey    struct{}
 	stateRevKey    struct{}
@@ -197,7 +196,7 @@ func (s *StateDB) AddRefund(gas uint64) {
 
 // AddPreimage stub.
 func (s *StateDB) AddPreimage(hash common.Hash, preimage []byte) {
-	s.repo.Put(preimageKey(hash), preimage)
+	s.state.AddPreimage(powerplay.Bytes32(hash), preimage)
 }
 
 // AddLog stub.
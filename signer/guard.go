@@ -0,0 +1,139 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package signer
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/playmakerchain/powerplay/bft"
+	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/kv"
+	"github.com/playmakerchain/powerplay/powerplay"
+)
+
+var (
+	guardPrefix     = []byte("sg") // (prefix, signer, block num) -> parent id + signing hash
+	voteGuardPrefix = []byte("sv") // (prefix, signer, block num) -> voted block id
+)
+
+// DoubleSignError returned when asked to sign a different block at a height already signed.
+type DoubleSignError struct {
+	Number   uint32
+	ParentID powerplay.Bytes32
+	Signed   powerplay.Bytes32
+}
+
+func (e *DoubleSignError) Error() string {
+	return fmt.Sprintf("refuse to double sign: block #%v (parent %v) already signed with signing hash %v",
+		e.Number, e.ParentID, e.Signed)
+}
+
+// DoubleVoteError returned when asked to vote a different block at a height already voted.
+type DoubleVoteError struct {
+	Number uint32
+	Voted  powerplay.Bytes32
+}
+
+func (e *DoubleVoteError) Error() string {
+	return fmt.Sprintf("refuse to double vote: block %v already voted at #%v", e.Voted, e.Number)
+}
+
+// IsDoubleSign returns whether the error is caused by slashing protection.
+func IsDoubleSign(err error) bool {
+	switch err.(type) {
+	case *DoubleSignError, *DoubleVoteError:
+		return true
+	}
+	return false
+}
+
+// Guard wraps a signer with slashing protection.
+// Every signed block is recorded by height, and signing a different block at the same height, which
+// is an equivocation, is refused. Re-signing the identical block is allowed. Finality votes are
+// guarded the same way.
+type Guard struct {
+	signer Signer
+	store  kv.GetPutter
+	lock   sync.Mutex
+}
+
+// NewGuard create a guard with records persisted in store.
+func NewGuard(signer Signer, store kv.GetPutter) *Guard {
+	return &Guard{signer: signer, store: store}
+}
+
+// Address returns address of the underlying signer.
+func (g *Guard) Address() powerplay.Address {
+	return g.signer.Address()
+}
+
+// SignBlock checks the header against signed records and signs it.
+func (g *Guard) SignBlock(header *block.Header) ([]byte, error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	key := g.recordKey(guardPrefix, header.Number())
+	signingHash := header.SigningHash()
+
+	data, err := g.store.Get(key)
+	if err != nil {
+		if !g.store.IsNotFound(err) {
+			return nil, err
+		}
+	} else if len(data) == 64 {
+		if powerplay.BytesToBytes32(data[32:]) != signingHash {
+			return nil, &DoubleSignError{
+				header.Number(),
+				powerplay.BytesToBytes32(data[:32]),
+				powerplay.BytesToBytes32(data[32:]),
+			}
+		}
+		return g.signer.SignBlock(header)
+	}
+
+	// record before signing, so that a crash in between never leads to double sign
+	parentID := header.ParentID()
+	if err := g.store.Put(key, append(parentID.Bytes(), signingHash.Bytes()...)); err != nil {
+		return nil, err
+	}
+	return g.signer.SignBlock(header)
+}
+
+// SignVote checks the vote against voted records and signs it.
+func (g *Guard) SignVote(vote *bft.Vote) ([]byte, error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	key := g.recordKey(voteGuardPrefix, vote.Number())
+
+	data, err := g.store.Get(key)
+	if err != nil {
+		if !g.store.IsNotFound(err) {
+			return nil, err
+		}
+	} else if len(data) == 32 {
+		if voted := powerplay.BytesToBytes32(data); voted != vote.BlockID {
+			return nil, &DoubleVoteError{vote.Number(), voted}
+		}
+		return g.signer.SignVote(vote)
+	}
+
+	if err := g.store.Put(key, vote.BlockID.Bytes()); err != nil {
+		return nil, err
+	}
+	return g.signer.SignVote(vote)
+}
+
+func (g *Guard) recordKey(prefix []byte, num uint32) []byte {
+	addr := g.signer.Address()
+	key := append([]byte(nil), prefix...)
+	key = append(key, addr.Bytes()...)
+	var numBytes [4]byte
+	binary.BigEndian.PutUint32(numBytes[:], num)
+	return append(key, numBytes[:]...)
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/bft"
+	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/powerplay"
+)
+
+const remoteTimeout = 5 * time.Second
+
+type addressResult struct {
+	Address *powerplay.Address `json:"address"`
+}
+
+type signRequest struct {
+	Header hexutil.Bytes `json:"header"` // rlp encoded header
+}
+
+type voteRequest struct {
+	BlockID powerplay.Bytes32 `json:"blockID"`
+}
+
+type signResult struct {
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// ParseEndpoint parses the signer endpoint into network and address.
+// The endpoint is either unix socket path like 'unix:///path/to/signer.sock', or TCP address like 'http://localhost:2850'.
+func ParseEndpoint(endpoint string) (network string, address string, err error) {
+	switch {
+	case strings.HasPrefix(endpoint, "unix://"):
+		network, address = "unix", strings.TrimPrefix(endpoint, "unix://")
+	case strings.HasPrefix(endpoint, "http://"):
+		network, address = "tcp", strings.TrimRight(strings.TrimPrefix(endpoint, "http://"), "/")
+	default:
+		return "", "", fmt.Errorf("unsupported signer endpoint %q", endpoint)
+	}
+	if address == "" {
+		return "", "", fmt.Errorf("invalid signer endpoint %q", endpoint)
+	}
+	return
+}
+
+type remoteSigner struct {
+	client *http.Client
+	url    string
+	token  string
+	addr   powerplay.Address
+}
+
+// NewRemote create a signer that forwards signing requests to the signer server at the endpoint.
+// token is sent as the bearer token if not empty.
+// The address of the remote key is queried once at creation.
+func NewRemote(endpoint string, token string) (Signer, error) {
+	network, address, err := ParseEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	url := "http://" + address
+	if network == "unix" {
+		// host part is ignored when dialing unix socket
+		url = "http://signer"
+	}
+	var dialer net.Dialer
+	s := &remoteSigner{
+		client: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					return dialer.DialContext(ctx, network, address)
+				},
+			},
+			Timeout: remoteTimeout,
+		},
+		url:   url,
+		token: token,
+	}
+
+	var result addressResult
+	if err := s.call("GET", "/address", nil, &result); err != nil {
+		return nil, errors.WithMessage(err, "query signer address")
+	}
+	if result.Address == nil {
+		return nil, errors.New("query signer address: empty address")
+	}
+	s.addr = *result.Address
+	return s, nil
+}
+
+func (s *remoteSigner) Address() powerplay.Address {
+	return s.addr
+}
+
+func (s *remoteSigner) SignBlock(header *block.Header) ([]byte, error) {
+	data, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	var result signResult
+	if err := s.call("POST", "/sign", &signRequest{data}, &result); err != nil {
+		return nil, err
+	}
+	return result.Signature, nil
+}
+
+func (s *remoteSigner) SignVote(vote *bft.Vote) ([]byte, error) {
+	var result signResult
+	if err := s.call("POST", "/vote", &voteRequest{vote.BlockID}, &result); err != nil {
+		return nil, err
+	}
+	return result.Signature, nil
+}
+
+func (s *remoteSigner) call(method, path string, body interface{}, result interface{}) error {
+	var reqBody []byte
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = data
+	}
+	req, err := http.NewRequest(method, s.url+path, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("signer: %v %v", res.Status, strings.TrimSpace(string(data)))
+	}
+	return json.Unmarshal(data, result)
+}
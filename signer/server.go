@@ -0,0 +1,92 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package signer
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/api/utils"
+	"github.com/playmakerchain/powerplay/bft"
+	"github.com/playmakerchain/powerplay/block"
+)
+
+// Server serves a signer over HTTP, to be reached by the remote signer.
+type Server struct {
+	signer Signer
+	token  string
+}
+
+// NewServer create a signer server.
+// If token is not empty, requests without it as the bearer token are refused.
+func NewServer(signer Signer, token string) *Server {
+	return &Server{signer, token}
+}
+
+func (s *Server) authorized(req *http.Request) bool {
+	if s.token == "" {
+		return true
+	}
+	expected := []byte("Bearer " + s.token)
+	return subtle.ConstantTimeCompare([]byte(req.Header.Get("Authorization")), expected) == 1
+}
+
+func (s *Server) handleAddress(w http.ResponseWriter, req *http.Request) error {
+	addr := s.signer.Address()
+	return utils.WriteJSON(w, &addressResult{&addr})
+}
+
+func (s *Server) handleSign(w http.ResponseWriter, req *http.Request) error {
+	var signReq signRequest
+	if err := utils.ParseJSON(req.Body, &signReq); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	var header block.Header
+	if err := rlp.DecodeBytes(signReq.Header, &header); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "header"))
+	}
+	sig, err := s.signer.SignBlock(&header)
+	if err != nil {
+		if IsDoubleSign(err) {
+			return utils.Forbidden(err)
+		}
+		return err
+	}
+	return utils.WriteJSON(w, &signResult{sig})
+}
+
+func (s *Server) handleVote(w http.ResponseWriter, req *http.Request) error {
+	var voteReq voteRequest
+	if err := utils.ParseJSON(req.Body, &voteReq); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	sig, err := s.signer.SignVote(bft.NewVote(voteReq.BlockID))
+	if err != nil {
+		if IsDoubleSign(err) {
+			return utils.Forbidden(err)
+		}
+		return err
+	}
+	return utils.WriteJSON(w, &signResult{sig})
+}
+
+// Handler returns the http handler of the server.
+func (s *Server) Handler() http.Handler {
+	router := mux.NewRouter()
+	router.Path("/address").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(s.handleAddress))
+	router.Path("/sign").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(s.handleSign))
+	router.Path("/vote").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(s.handleVote))
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !s.authorized(req) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		router.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package signer
+
+import (
+	"crypto/ecdsa"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/playmakerchain/powerplay/bft"
+	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/powerplay"
+)
+
+// Signer signs block headers and finality votes on behalf of the block proposer.
+type Signer interface {
+	// Address returns address of the signing key.
+	Address() powerplay.Address
+	// SignBlock returns the signature over the signing hash of the header.
+	SignBlock(header *block.Header) ([]byte, error)
+	// SignVote returns the signature over the signing hash of the vote.
+	SignVote(vote *bft.Vote) ([]byte, error)
+}
+
+type keySigner struct {
+	key  *ecdsa.PrivateKey
+	addr powerplay.Address
+}
+
+// NewKeySigner create a signer holding the private key in process.
+func NewKeySigner(key *ecdsa.PrivateKey) Signer {
+	return &keySigner{
+		key,
+		powerplay.Address(crypto.PubkeyToAddress(key.PublicKey)),
+	}
+}
+
+func (s *keySigner) Address() powerplay.Address {
+	return s.addr
+}
+
+func (s *keySigner) SignBlock(header *block.Header) ([]byte, error) {
+	return crypto.Sign(header.SigningHash().Bytes(), s.key)
+}
+
+func (s *keySigner) SignVote(vote *bft.Vote) ([]byte, error) {
+	return crypto.Sign(vote.SigningHash().Bytes(), s.key)
+}
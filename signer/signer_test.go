@@ -0,0 +1,127 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package signer
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/playmakerchain/powerplay/bft"
+	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/lvldb"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/stretchr/testify/assert"
+)
+
+func newHeader(parentID powerplay.Bytes32, timestamp uint64) *block.Header {
+	return new(block.Builder).
+		ParentID(parentID).
+		Timestamp(timestamp).
+		Build().Header()
+}
+
+func checkSignature(t *testing.T, header *block.Header, sig []byte, addr powerplay.Address) {
+	pub, err := crypto.SigToPub(header.SigningHash().Bytes(), sig)
+	assert.Nil(t, err)
+	assert.Equal(t, addr, powerplay.Address(crypto.PubkeyToAddress(*pub)))
+}
+
+func TestGuard(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	key, _ := crypto.GenerateKey()
+	s := NewKeySigner(key)
+	g := NewGuard(s, db)
+	assert.Equal(t, s.Address(), g.Address())
+
+	parent := powerplay.BytesToBytes32([]byte("parent"))
+	h1 := newHeader(parent, 10)
+	sig, err := g.SignBlock(h1)
+	assert.Nil(t, err)
+	checkSignature(t, h1, sig, s.Address())
+
+	_, err = g.SignBlock(h1)
+	assert.Nil(t, err, "re-sign the same block is allowed")
+
+	_, err = g.SignBlock(newHeader(parent, 20))
+	assert.True(t, IsDoubleSign(err), "same height and parent")
+
+	_, err = g.SignBlock(newHeader(powerplay.BytesToBytes32([]byte("other")), 10))
+	assert.True(t, IsDoubleSign(err), "same height, different parent")
+
+	// records persisted
+	_, err = NewGuard(s, db).SignBlock(newHeader(parent, 20))
+	assert.True(t, IsDoubleSign(err))
+
+	// records are per signer
+	key2, _ := crypto.GenerateKey()
+	_, err = NewGuard(NewKeySigner(key2), db).SignBlock(newHeader(parent, 20))
+	assert.Nil(t, err)
+}
+
+func TestGuardVote(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	key, _ := crypto.GenerateKey()
+	g := NewGuard(NewKeySigner(key), db)
+
+	var id1, id2 powerplay.Bytes32
+	id1[3], id1[31] = 10, 1
+	id2[3], id2[31] = 10, 2
+
+	vote := bft.NewVote(id1)
+	sig, err := g.SignVote(vote)
+	assert.Nil(t, err)
+	signer, err := vote.WithSignature(sig).Signer()
+	assert.Nil(t, err)
+	assert.Equal(t, g.Address(), signer)
+
+	_, err = g.SignVote(vote)
+	assert.Nil(t, err, "re-vote the same block is allowed")
+
+	_, err = g.SignVote(bft.NewVote(id2))
+	assert.True(t, IsDoubleSign(err), "same height, different block")
+
+	// votes and blocks are guarded separately
+	_, err = g.SignBlock(newHeader(powerplay.BytesToBytes32([]byte("parent")), 10))
+	assert.Nil(t, err)
+}
+
+func TestRemote(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	key, _ := crypto.GenerateKey()
+	guard := NewGuard(NewKeySigner(key), db)
+
+	srv := httptest.NewServer(NewServer(guard, "secret").Handler())
+	defer srv.Close()
+
+	_, err := NewRemote(srv.URL, "")
+	assert.NotNil(t, err, "token required")
+	_, err = NewRemote(srv.URL, "wrong")
+	assert.NotNil(t, err, "token mismatch")
+
+	remote, err := NewRemote(srv.URL, "secret")
+	assert.Nil(t, err)
+	assert.Equal(t, guard.Address(), remote.Address())
+
+	parent := powerplay.BytesToBytes32([]byte("parent"))
+	h := newHeader(parent, 10)
+	sig, err := remote.SignBlock(h)
+	assert.Nil(t, err)
+	checkSignature(t, h, sig, guard.Address())
+
+	_, err = remote.SignBlock(newHeader(parent, 20))
+	assert.NotNil(t, err, "double sign refused by server")
+
+	vote := bft.NewVote(h.ID())
+	sig, err = remote.SignVote(vote)
+	assert.Nil(t, err)
+	signer, err := vote.WithSignature(sig).Signer()
+	assert.Nil(t, err)
+	assert.Equal(t, guard.Address(), signer)
+
+	_, err = NewRemote("tcp://"+srv.Listener.Addr().String(), "secret")
+	assert.NotNil(t, err, "unsupported endpoint")
+}
@@ -28,6 +28,7 @@ func newLevel() *level {
 type journalEntry struct {
 	key   interface{}
 	value interface{}
+	tag   interface{}
 }
 
 // MapGetter defines getter method of map.
@@ -92,9 +93,14 @@ func (sm *StackedMap) Get(key interface{}) (interface{}, bool) {
 // Put puts key value into map at stack top.
 // It will panic if stack is empty.
 func (sm *StackedMap) Put(key, value interface{}) {
+	sm.PutWithTag(key, value, nil)
+}
+
+// PutWithTag puts key value into map at stack top, like Put, and attaches the tag to the journal entry.
+func (sm *StackedMap) PutWithTag(key, value, tag interface{}) {
 	top := sm.mapStack.top().(*level)
 	top.kvs[key] = value
-	top.journal = append(top.journal, &journalEntry{key: key, value: value})
+	top.journal = append(top.journal, &journalEntry{key: key, value: value, tag: tag})
 
 	// records key revision for fast access
 	rev := len(sm.mapStack) - 1
@@ -119,6 +125,17 @@ func (sm *StackedMap) Journal(cb func(key, value interface{}) bool) {
 	}
 }
 
+// TaggedJournal traverse journal entries like Journal, along with tags attached by PutWithTag.
+func (sm *StackedMap) TaggedJournal(cb func(key, value, tag interface{}) bool) {
+	for _, lvl := range sm.mapStack {
+		for _, entry := range lvl.(*level).journal {
+			if !cb(entry.key, entry.value, entry.tag) {
+				return
+			}
+		}
+	}
+}
+
 // stack ops
 type stack []interface{}
 
@@ -96,3 +96,22 @@ func TestStackedMapPuts(t *testing.T) {
 
 	assert.Equal(1, i, "Journal traverse should abort")
 }
+
+func TestStackedMapTaggedJournal(t *testing.T) {
+	sm := stackedmap.New(func(key interface{}) (interface{}, bool) {
+		return nil, false
+	})
+
+	sm.Put("a", "b")
+	sm.PutWithTag("c", "d", "tag")
+	sm.Push()
+	sm.PutWithTag("e", "f", "reverted")
+	sm.Pop()
+
+	var entries [][3]interface{}
+	sm.TaggedJournal(func(k, v, tag interface{}) bool {
+		entries = append(entries, [3]interface{}{k, v, tag})
+		return true
+	})
+	assert.Equal(t, [][3]interface{}{{"a", "b", nil}, {"c", "d", "tag"}}, entries)
+}
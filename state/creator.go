@@ -13,15 +13,31 @@ import (
 
 // Creator state creator to cut-off kv dependency.
 type Creator struct {
-	kv kv.GetPutter
+	kv              kv.GetPutter
+	recordPreimages bool
 }
 
 // NewCreator create a new state creator.
 func NewCreator(kv kv.GetPutter) *Creator {
-	return &Creator{kv}
+	return &Creator{kv: kv}
+}
+
+// SetPreimageRecording sets whether states created record preimages.
+func (c *Creator) SetPreimageRecording(enabled bool) {
+	c.recordPreimages = enabled
 }
 
 // NewState create a new state object.
 func (c *Creator) NewState(root powerplay.Bytes32) (*State, error) {
-	return New(root, c.kv)
+	state, err := New(root, c.kv)
+	if err != nil {
+		return nil, err
+	}
+	state.SetPreimageRecording(c.recordPreimages)
+	return state, nil
+}
+
+// GetPreimage returns the persisted preimage of the hash, or nil if not recorded.
+func (c *Creator) GetPreimage(hash powerplay.Bytes32) ([]byte, error) {
+	return loadPreimage(c.kv, hash)
 }
@@ -0,0 +1,305 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/kv"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/trie"
+)
+
+var (
+	historyPrefix        = []byte("h")
+	historyHeadKey       = []byte("hh")
+	historyAccountPrefix = []byte("ha") // (prefix, account key hash, ^block num) -> account
+	historyStoragePrefix = []byte("hs") // (prefix, account key hash, storage key hash, ^block num) -> storage value
+	historyJournalPrefix = []byte("hj") // (prefix, block num) -> journal
+)
+
+// historyJournal records keys written for a block, to revert the block.
+type historyJournal struct {
+	BlockID powerplay.Bytes32
+	Keys    [][]byte
+}
+
+// History records per-account change history of trunk blocks in a dedicated index.
+// Accounts and storage at any recorded block are resolved by seeking the index,
+// without historical tries.
+type History struct {
+	kv kv.GetPutter
+}
+
+// NewHistory create a history instance.
+func NewHistory(kv kv.GetPutter) *History {
+	return &History{kv}
+}
+
+// IsHistoryKey returns whether the key in kv store belongs to history index.
+func IsHistoryKey(key []byte) bool {
+	// trie nodes and codes are keyed by 32 bytes hash
+	return len(key) != len(powerplay.Bytes32{}) && bytes.HasPrefix(key, historyPrefix)
+}
+
+// HeadID returns ID of the last recorded block.
+// ok is false if nothing recorded.
+func (h *History) HeadID() (id powerplay.Bytes32, ok bool, err error) {
+	data, err := h.kv.Get(historyHeadKey)
+	if err != nil {
+		if h.kv.IsNotFound(err) {
+			return powerplay.Bytes32{}, false, nil
+		}
+		return powerplay.Bytes32{}, false, err
+	}
+	return powerplay.BytesToBytes32(data), true, nil
+}
+
+// BlockID returns ID of the recorded block at num.
+func (h *History) BlockID(num uint32) (powerplay.Bytes32, error) {
+	journal, err := h.loadJournal(num)
+	if err != nil {
+		return powerplay.Bytes32{}, err
+	}
+	return journal.BlockID, nil
+}
+
+// IsNotFound returns whether the error indicates block not recorded.
+func (h *History) IsNotFound(err error) bool {
+	return h.kv.IsNotFound(err)
+}
+
+// Record records changes made by the block, by comparing state tries of the block and its parent.
+// Blocks must be recorded in sequence, starting from genesis whose parent state root is zero.
+func (h *History) Record(blockID, parentStateRoot, stateRoot powerplay.Bytes32) error {
+	num := binary.BigEndian.Uint32(blockID[:])
+	headID, ok, err := h.HeadID()
+	if err != nil {
+		return err
+	}
+	if ok {
+		if binary.BigEndian.Uint32(headID[:])+1 != num {
+			return errors.New("block not in sequence")
+		}
+	} else if num != 0 {
+		return errors.New("genesis not recorded")
+	}
+
+	batch := h.kv.NewBatch()
+	journal := historyJournal{BlockID: blockID}
+	put := func(key, value []byte) error {
+		key = appendNum(key, ^num)
+		journal.Keys = append(journal.Keys, key)
+		return batch.Put(key, value)
+	}
+
+	if err := diffTries(h.kv, parentStateRoot, stateRoot, func(key, oldValue, newValue []byte) error {
+		if err := put(concatBytes(historyAccountPrefix, key), newValue); err != nil {
+			return err
+		}
+		oldRoot, err := storageRootOf(oldValue)
+		if err != nil {
+			return err
+		}
+		newRoot, err := storageRootOf(newValue)
+		if err != nil {
+			return err
+		}
+		if oldRoot == newRoot {
+			return nil
+		}
+		return diffTries(h.kv, oldRoot, newRoot, func(storageKey, _, value []byte) error {
+			return put(concatBytes(historyStoragePrefix, key, storageKey), value)
+		})
+	}); err != nil {
+		return errors.WithMessage(err, "diff tries")
+	}
+
+	data, err := rlp.EncodeToBytes(&journal)
+	if err != nil {
+		return err
+	}
+	if err := batch.Put(appendNum(historyJournalPrefix, num), data); err != nil {
+		return err
+	}
+	if err := batch.Put(historyHeadKey, blockID[:]); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// Revert removes records of the last recorded block.
+func (h *History) Revert() error {
+	headID, ok, err := h.HeadID()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("nothing recorded")
+	}
+	num := binary.BigEndian.Uint32(headID[:])
+	journal, err := h.loadJournal(num)
+	if err != nil {
+		return err
+	}
+
+	batch := h.kv.NewBatch()
+	for _, key := range journal.Keys {
+		if err := batch.Delete(key); err != nil {
+			return err
+		}
+	}
+	if err := batch.Delete(appendNum(historyJournalPrefix, num)); err != nil {
+		return err
+	}
+	if num == 0 {
+		if err := batch.Delete(historyHeadKey); err != nil {
+			return err
+		}
+	} else {
+		parent, err := h.loadJournal(num - 1)
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(historyHeadKey, parent.BlockID[:]); err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+// NewState create a read-only state of the recorded block, resolved from history index.
+func (h *History) NewState(blockID powerplay.Bytes32) (*State, error) {
+	num := binary.BigEndian.Uint32(blockID[:])
+	recordedID, err := h.BlockID(num)
+	if err != nil {
+		return nil, err
+	}
+	if recordedID != blockID {
+		return nil, errors.New("block not recorded")
+	}
+
+	s := newState(powerplay.Bytes32{}, h.kv, &historyReader{h, num, historyAccountPrefix})
+	s.newStorageTrie = func(addr powerplay.Address) trieReader {
+		return &historyReader{h, num, concatBytes(historyStoragePrefix, powerplay.Blake2b(addr[:]).Bytes())}
+	}
+	return s, nil
+}
+
+func (h *History) loadJournal(num uint32) (*historyJournal, error) {
+	data, err := h.kv.Get(appendNum(historyJournalPrefix, num))
+	if err != nil {
+		return nil, err
+	}
+	var journal historyJournal
+	if err := rlp.DecodeBytes(data, &journal); err != nil {
+		return nil, err
+	}
+	return &journal, nil
+}
+
+// get returns the value of the key hash at block num, or nil if never set.
+func (h *History) get(prefix []byte, num uint32) ([]byte, error) {
+	it := h.kv.NewIterator(kv.Range{
+		From: appendNum(prefix, ^num),
+		To:   kv.NewRangeWithBytesPrefix(prefix).To,
+	})
+	defer it.Release()
+	if it.Next() {
+		return append([]byte(nil), it.Value()...), nil
+	}
+	return nil, it.Error()
+}
+
+// historyReader implements trieReader by seeking history index.
+type historyReader struct {
+	history *History
+	num     uint32
+	prefix  []byte
+}
+
+func (r *historyReader) TryGet(key []byte) ([]byte, error) {
+	return r.history.get(concatBytes(r.prefix, powerplay.Blake2b(key).Bytes()), r.num)
+}
+
+// diffTries calls cb with leaves added, updated or deleted from trie a to trie b.
+// Keys passed to cb are hashed keys of secure tries.
+func diffTries(kv kv.GetPutter, a, b powerplay.Bytes32, cb func(key, aValue, bValue []byte) error) error {
+	trieA, err := trie.New(a, kv)
+	if err != nil {
+		return err
+	}
+	trieB, err := trie.New(b, kv)
+	if err != nil {
+		return err
+	}
+
+	// added or updated
+	it, _ := trie.NewDifferenceIterator(trieA.NodeIterator(nil), trieB.NodeIterator(nil))
+	for it.Next(true) {
+		if it.Leaf() {
+			aValue, err := trieA.TryGet(it.LeafKey())
+			if err != nil {
+				return err
+			}
+			if err := cb(it.LeafKey(), aValue, it.LeafBlob()); err != nil {
+				return err
+			}
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	// deleted
+	it, _ = trie.NewDifferenceIterator(trieB.NodeIterator(nil), trieA.NodeIterator(nil))
+	for it.Next(true) {
+		if it.Leaf() {
+			bValue, err := trieB.TryGet(it.LeafKey())
+			if err != nil {
+				return err
+			}
+			if len(bValue) == 0 {
+				if err := cb(it.LeafKey(), it.LeafBlob(), nil); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return it.Error()
+}
+
+func storageRootOf(data []byte) (powerplay.Bytes32, error) {
+	if len(data) == 0 {
+		return powerplay.Bytes32{}, nil
+	}
+	var a Account
+	if err := rlp.DecodeBytes(data, &a); err != nil {
+		return powerplay.Bytes32{}, err
+	}
+	return powerplay.BytesToBytes32(a.StorageRoot), nil
+}
+
+func appendNum(prefix []byte, num uint32) []byte {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], num)
+	return concatBytes(prefix, b[:])
+}
+
+func concatBytes(slices ...[]byte) []byte {
+	var n int
+	for _, s := range slices {
+		n += len(s)
+	}
+	b := make([]byte, 0, n)
+	for _, s := range slices {
+		b = append(b, s...)
+	}
+	return b
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/playmakerchain/powerplay/lvldb"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistory(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	history := NewHistory(kv)
+
+	addr := powerplay.BytesToAddress([]byte("account1"))
+	key := powerplay.BytesToBytes32([]byte("key"))
+
+	blockID := func(num uint32) (id powerplay.Bytes32) {
+		binary.BigEndian.PutUint32(id[:], num)
+		id[31] = 1
+		return
+	}
+
+	// block 0: set balance and storage
+	// block 1: update storage
+	// block 2: delete account
+	var roots []powerplay.Bytes32
+	var parentRoot powerplay.Bytes32
+	for i := uint32(0); i < 3; i++ {
+		st, _ := New(parentRoot, kv)
+		switch i {
+		case 0:
+			st.SetBalance(addr, big.NewInt(10))
+			st.SetStorage(addr, key, powerplay.BytesToBytes32([]byte("v0")))
+		case 1:
+			st.SetStorage(addr, key, powerplay.BytesToBytes32([]byte("v1")))
+		case 2:
+			st.Delete(addr)
+		}
+		root, err := st.Stage().Commit()
+		assert.Nil(t, err)
+		assert.Nil(t, history.Record(blockID(i), parentRoot, root))
+		roots = append(roots, root)
+		parentRoot = root
+	}
+
+	assert.NotNil(t, history.Record(blockID(5), parentRoot, parentRoot), "should be in sequence")
+
+	expected := []struct {
+		balance int64
+		value   powerplay.Bytes32
+	}{
+		{10, powerplay.BytesToBytes32([]byte("v0"))},
+		{10, powerplay.BytesToBytes32([]byte("v1"))},
+		{0, powerplay.Bytes32{}},
+	}
+	for i, e := range expected {
+		st, err := history.NewState(blockID(uint32(i)))
+		assert.Nil(t, err)
+		assert.Equal(t, e.balance, st.GetBalance(addr).Int64())
+		assert.Equal(t, e.value, st.GetStorage(addr, key))
+		assert.Nil(t, st.Err())
+
+		_, err = st.Stage().Hash()
+		assert.NotNil(t, err, "should be read-only")
+	}
+
+	assert.Nil(t, history.Revert())
+	headID, ok, err := history.HeadID()
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, blockID(1), headID)
+
+	_, err = history.NewState(blockID(2))
+	assert.True(t, history.IsNotFound(err))
+
+	st, _ := history.NewState(blockID(1))
+	assert.Equal(t, powerplay.BytesToBytes32([]byte("v1")), st.GetStorage(addr, key))
+}
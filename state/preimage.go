@@ -0,0 +1,40 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"github.com/playmakerchain/powerplay/kv"
+	"github.com/playmakerchain/powerplay/powerplay"
+)
+
+// (prefix, hash) -> preimage
+// hash can be keccak256 computed by SHA3 op code, or blake2b hashed key of secure tries.
+var preimagePrefix = []byte("p")
+
+func preimageDBKey(hash powerplay.Bytes32) []byte {
+	return concatBytes(preimagePrefix, hash[:])
+}
+
+// IsPreimageKey returns whether the key in kv store belongs to preimages.
+func IsPreimageKey(key []byte) bool {
+	return len(key) == len(preimagePrefix)+len(powerplay.Bytes32{}) && key[0] == preimagePrefix[0]
+}
+
+func savePreimage(w kv.Putter, hash powerplay.Bytes32, preimage []byte) error {
+	return w.Put(preimageDBKey(hash), preimage)
+}
+
+// loadPreimage returns nil if preimage not recorded.
+func loadPreimage(r kv.Getter, hash powerplay.Bytes32) ([]byte, error) {
+	preimage, err := r.Get(preimageDBKey(hash))
+	if err != nil {
+		if r.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return preimage, nil
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/playmakerchain/powerplay/lvldb"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreimageRecording(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+
+	addr := powerplay.BytesToAddress([]byte("account1"))
+	data := []byte("mapping key")
+	key := powerplay.Bytes32(crypto.Keccak256Hash(data))
+	value := powerplay.BytesToBytes32([]byte("value"))
+
+	for _, enabled := range []bool{false, true} {
+		creator := NewCreator(kv)
+		creator.SetPreimageRecording(enabled)
+		state, _ := creator.NewState(powerplay.Bytes32{})
+
+		state.AddPreimage(key, data)
+		state.SetStorage(addr, key, value)
+		_, err := state.Stage().Commit()
+		assert.Nil(t, err)
+
+		preimage, err := creator.GetPreimage(key)
+		assert.Nil(t, err)
+		storageKeyPreimage, err := creator.GetPreimage(powerplay.Blake2b(key[:]))
+		assert.Nil(t, err)
+		if enabled {
+			assert.Equal(t, data, preimage)
+			assert.Equal(t, key[:], storageKeyPreimage)
+		} else {
+			assert.Nil(t, preimage)
+			assert.Nil(t, storageKeyPreimage)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/stackedmap"
+)
+
+// modifier computes new value from the old one. It should be pure, since it's applied again
+// when a speculation is merged.
+type modifier func(value interface{}) (interface{}, error)
+
+// NewSpeculation create a state layered upon the current state, to execute a tx speculatively.
+// Values read through the current state are recorded, so that the speculation can be validated and
+// merged back by Merge, after other changes made to the current state.
+// Speculations are safe to be used concurrently, as long as the current state is not accessed meanwhile.
+func (s *State) NewSpeculation() *State {
+	spec := &State{
+		root:            s.root,
+		kv:              s.kv,
+		cache:           make(map[powerplay.Address]*cachedObject),
+		recordPreimages: s.recordPreimages,
+		base:            s,
+		reads:           make(map[interface{}]interface{}),
+		modified:        make(map[interface{}]interface{}),
+	}
+	spec.setError = func(err error) {
+		if spec.err == nil {
+			spec.err = err
+		}
+	}
+	spec.sm = stackedmap.New(func(key interface{}) (interface{}, bool) {
+		s.lock.Lock()
+		v := s.get(key)
+		s.lock.Unlock()
+
+		if spec.unrecorded {
+			if _, ok := spec.modified[key]; !ok {
+				spec.modified[key] = v
+			}
+		} else {
+			spec.recordRead(key, v)
+		}
+		return v, true
+	})
+	return spec
+}
+
+func (s *State) recordRead(key, value interface{}) {
+	if _, ok := s.reads[key]; !ok {
+		s.reads[key] = value
+	}
+}
+
+// modify updates value of the key by the modifier. Unlike get-then-put, the old value is not
+// recorded as read by speculations, and the modifier is applied upon the latest value when merged.
+func (s *State) modify(key interface{}, m modifier) {
+	s.unrecorded = true
+	v, _ := s.sm.Get(key)
+	s.unrecorded = false
+
+	newValue, err := m(v)
+	if err != nil {
+		s.setError(err)
+		return
+	}
+	s.sm.PutWithTag(key, newValue, m)
+}
+
+// AddEnergy adds energy to the given address at block time. It's equivalent to SetEnergy
+// with added amount, but speculations adding energy to the same address don't conflict.
+func (s *State) AddEnergy(addr powerplay.Address, amount *big.Int, blockTime uint64) {
+	s.modify(addr, func(v interface{}) (interface{}, error) {
+		cpy := *v.(*Account)
+		cpy.Energy, cpy.BlockTime = new(big.Int).Add(cpy.CalcEnergy(blockTime), amount), blockTime
+		return &cpy, nil
+	})
+}
+
+// ModifyRawStorage updates storage value in rlp raw by fn, which should be pure.
+// Speculations modifying the same storage don't conflict, e.g. to accumulate counters.
+func (s *State) ModifyRawStorage(addr powerplay.Address, key powerplay.Bytes32, fn func(raw rlp.RawValue) (rlp.RawValue, error)) {
+	s.modify(storageKey{addr, key}, func(v interface{}) (interface{}, error) {
+		raw, err := fn(v.(rlp.RawValue))
+		if err != nil {
+			return nil, err
+		}
+		return raw, nil
+	})
+}
+
+// Validate returns whether values read by the speculation are still the same in the current state.
+func (s *State) Validate(spec *State) bool {
+	if spec.base != s || spec.err != nil {
+		return false
+	}
+	for key, v := range spec.reads {
+		if !sameValue(s.get(key), v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Merge applies changes of the speculation to the current state, as if they were made directly.
+// False is returned without any change, if the speculation fails to validate.
+func (s *State) Merge(spec *State) bool {
+	if !s.Validate(spec) {
+		return false
+	}
+	spec.sm.TaggedJournal(func(key, value, tag interface{}) bool {
+		if m, ok := tag.(modifier); ok {
+			s.modify(key, m)
+		} else {
+			s.sm.Put(key, value)
+		}
+		return true
+	})
+	return true
+}
+
+func sameValue(a, b interface{}) bool {
+	switch a := a.(type) {
+	case *Account:
+		// accounts are never modified in place, so compare by identity
+		b, ok := b.(*Account)
+		return ok && a == b
+	case rlp.RawValue:
+		b, ok := b.(rlp.RawValue)
+		return ok && bytes.Equal(a, b)
+	case []byte:
+		b, ok := b.([]byte)
+		return ok && bytes.Equal(a, b)
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/playmakerchain/powerplay/lvldb"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpeculationMerge(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	st, _ := New(powerplay.Bytes32{}, kv)
+
+	addr1 := powerplay.BytesToAddress([]byte("addr1"))
+	addr2 := powerplay.BytesToAddress([]byte("addr2"))
+	st.SetBalance(addr1, big.NewInt(10))
+
+	spec := st.NewSpeculation()
+	assert.Equal(t, big.NewInt(10), spec.GetBalance(addr1), "base visible")
+	spec.SetBalance(addr2, big.NewInt(20))
+	assert.Equal(t, &big.Int{}, st.GetBalance(addr2), "base untouched")
+
+	assert.NotNil(t, spec.Stage().err, "speculation can't be staged")
+
+	assert.True(t, st.Merge(spec))
+	assert.Equal(t, big.NewInt(20), st.GetBalance(addr2))
+}
+
+func TestSpeculationConflict(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	st, _ := New(powerplay.Bytes32{}, kv)
+
+	addr := powerplay.BytesToAddress([]byte("addr"))
+	st.SetBalance(addr, big.NewInt(10))
+
+	spec1 := st.NewSpeculation()
+	spec1.SetBalance(addr, new(big.Int).Add(spec1.GetBalance(addr), big.NewInt(1)))
+
+	spec2 := st.NewSpeculation()
+	spec2.SetBalance(addr, new(big.Int).Add(spec2.GetBalance(addr), big.NewInt(2)))
+
+	// value read by an unrelated speculation is not changed
+	spec3 := st.NewSpeculation()
+	spec3.GetBalance(powerplay.BytesToAddress([]byte("other")))
+
+	assert.True(t, st.Merge(spec1))
+	assert.False(t, st.Validate(spec2), "read value changed")
+	assert.False(t, st.Merge(spec2))
+	assert.Equal(t, big.NewInt(11), st.GetBalance(addr))
+	assert.True(t, st.Merge(spec3))
+
+	other, _ := New(powerplay.Bytes32{}, kv)
+	assert.False(t, other.Validate(st.NewSpeculation()), "different base")
+}
+
+func TestSpeculationModify(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	st, _ := New(powerplay.Bytes32{}, kv)
+
+	addr := powerplay.BytesToAddress([]byte("addr"))
+	key := powerplay.BytesToBytes32([]byte("counter"))
+	st.SetEnergy(addr, big.NewInt(100), 0)
+
+	inc := func(n uint64) func(raw rlp.RawValue) (rlp.RawValue, error) {
+		return func(raw rlp.RawValue) (rlp.RawValue, error) {
+			var v uint64
+			if len(raw) > 0 {
+				if err := rlp.DecodeBytes(raw, &v); err != nil {
+					return nil, err
+				}
+			}
+			return rlp.EncodeToBytes(v + n)
+		}
+	}
+
+	var specs []*State
+	for i := 1; i <= 3; i++ {
+		spec := st.NewSpeculation()
+		spec.AddEnergy(addr, big.NewInt(int64(i)), 0)
+		spec.ModifyRawStorage(addr, key, inc(uint64(i)))
+		specs = append(specs, spec)
+	}
+	for _, spec := range specs {
+		assert.True(t, st.Merge(spec), "modifications don't conflict")
+	}
+
+	assert.Equal(t, big.NewInt(106), st.GetEnergy(addr, 0))
+	var counter uint64
+	assert.Nil(t, rlp.DecodeBytes(st.GetRawStorage(addr, key), &counter))
+	assert.Equal(t, uint64(6), counter)
+	assert.Nil(t, st.Err())
+}
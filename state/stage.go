@@ -20,6 +20,7 @@ type Stage struct {
 	accountTrie  *trie.SecureTrie
 	storageTries []*trie.SecureTrie
 	codes        []codeWithHash
+	preimages    map[powerplay.Bytes32][]byte
 }
 
 type codeWithHash struct {
@@ -27,7 +28,7 @@ type codeWithHash struct {
 	hash []byte
 }
 
-func newStage(root powerplay.Bytes32, kv kv.GetPutter, changes map[powerplay.Address]*changedObject) *Stage {
+func newStage(root powerplay.Bytes32, kv kv.GetPutter, changes map[powerplay.Address]*changedObject, preimages map[powerplay.Bytes32][]byte) *Stage {
 
 	accountTrie, err := trCache.Get(root, kv, true)
 	if err != nil {
@@ -72,6 +73,7 @@ func newStage(root powerplay.Bytes32, kv kv.GetPutter, changes map[powerplay.Add
 		accountTrie:  accountTrie,
 		storageTries: storageTries,
 		codes:        codes,
+		preimages:    preimages,
 	}
 }
 
@@ -96,6 +98,13 @@ func (s *Stage) Commit() (powerplay.Bytes32, error) {
 		}
 	}
 
+	// write preimages
+	for hash, preimage := range s.preimages {
+		if err := savePreimage(batch, hash, preimage); err != nil {
+			return powerplay.Bytes32{}, err
+		}
+	}
+
 	// commit storage tries
 	for _, strie := range s.storageTries {
 		root, err := strie.CommitTo(batch)
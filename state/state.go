@@ -10,9 +10,11 @@ import (
 	"bytes"
 	"fmt"
 	"math/big"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
 	"github.com/playmakerchain/powerplay/kv"
 	"github.com/playmakerchain/powerplay/stackedmap"
 	"github.com/playmakerchain/powerplay/powerplay"
@@ -28,6 +30,18 @@ type State struct {
 	sm       *stackedmap.StackedMap         // keeps revisions of accounts state
 	err      error
 	setError func(err error)
+
+	// to override storage tries, set for read-only states
+	newStorageTrie func(addr powerplay.Address) trieReader
+
+	recordPreimages bool
+
+	// for speculative states, see NewSpeculation
+	base       *State
+	reads      map[interface{}]interface{} // key -> value read through base
+	modified   map[interface{}]interface{} // key -> base value consumed by modifiers only
+	unrecorded bool
+	lock       sync.Mutex // serializes reads of speculations upon this state
 }
 
 // to constrain ability of trie
@@ -47,7 +61,10 @@ func New(root powerplay.Bytes32, kv kv.GetPutter) (*State, error) {
 	if err != nil {
 		return nil, err
 	}
+	return newState(root, kv, trie), nil
+}
 
+func newState(root powerplay.Bytes32, kv kv.GetPutter, trie trieReader) *State {
 	state := State{
 		root:  root,
 		kv:    kv,
@@ -62,7 +79,7 @@ func New(root powerplay.Bytes32, kv kv.GetPutter) (*State, error) {
 	state.sm = stackedmap.New(func(key interface{}) (value interface{}, exist bool) {
 		return state.cacheGetter(key)
 	})
-	return &state, nil
+	return &state
 }
 
 // Spawn create a new state object shares current state's underlying db.
@@ -97,6 +114,8 @@ func (s *State) cacheGetter(key interface{}) (value interface{}, exist bool) {
 			return rlp.RawValue(nil), true
 		}
 		return v, true
+	case preimageKey: // get preimage
+		return []byte(nil), true
 	}
 	panic(fmt.Errorf("unexpected key type %+v", key))
 }
@@ -145,14 +164,28 @@ func (s *State) getCachedObject(addr powerplay.Address) *cachedObject {
 		return newCachedObject(s.kv, emptyAccount())
 	}
 	co := newCachedObject(s.kv, a)
+	if s.newStorageTrie != nil {
+		co.cache.storageTrie = s.newStorageTrie(addr)
+	}
 	s.cache[addr] = co
 	return co
 }
 
+// get gets value from the stacked map. For speculative states, the base value consumed by
+// modifiers is recorded as read once the key is read.
+func (s *State) get(key interface{}) interface{} {
+	v, _ := s.sm.Get(key)
+	if s.base != nil {
+		if bv, ok := s.modified[key]; ok {
+			s.recordRead(key, bv)
+		}
+	}
+	return v
+}
+
 // the returned account should not be modified
 func (s *State) getAccount(addr powerplay.Address) *Account {
-	v, _ := s.sm.Get(addr)
-	return v.(*Account)
+	return s.get(addr).(*Account)
 }
 
 func (s *State) getAccountCopy(addr powerplay.Address) Account {
@@ -287,8 +320,7 @@ func (s *State) SetStorage(addr powerplay.Address, key, value powerplay.Bytes32)
 
 // GetRawStorage returns storage value in rlp raw for given address and key.
 func (s *State) GetRawStorage(addr powerplay.Address, key powerplay.Bytes32) rlp.RawValue {
-	data, _ := s.sm.Get(storageKey{addr, key})
-	return data.(rlp.RawValue)
+	return s.get(storageKey{addr, key}).(rlp.RawValue)
 }
 
 // SetRawStorage set storage value in rlp raw.
@@ -318,8 +350,7 @@ func (s *State) DecodeStorage(addr powerplay.Address, key powerplay.Bytes32, dec
 
 // GetCode returns code for the given address.
 func (s *State) GetCode(addr powerplay.Address) []byte {
-	v, _ := s.sm.Get(codeKey(addr))
-	return v.([]byte)
+	return s.get(codeKey(addr)).([]byte)
 }
 
 // GetCodeHash returns code hash for the given address.
@@ -354,6 +385,30 @@ func (s *State) Delete(addr powerplay.Address) {
 	s.updateAccount(addr, emptyAccount())
 }
 
+// SetPreimageRecording sets whether to record preimages of hashes.
+// Recorded preimages are persisted when the state is committed.
+func (s *State) SetPreimageRecording(enabled bool) {
+	s.recordPreimages = enabled
+}
+
+// IsRecordingPreimages returns whether preimages are being recorded.
+func (s *State) IsRecordingPreimages() bool {
+	return s.recordPreimages
+}
+
+// AddPreimage records preimage of the hash, e.g. input of SHA3 op code.
+// It's no-op unless preimage recording enabled.
+func (s *State) AddPreimage(hash powerplay.Bytes32, preimage []byte) {
+	if s.recordPreimages {
+		s.sm.Put(preimageKey(hash), preimage)
+	}
+}
+
+// GetPreimage returns the persisted preimage of the hash, or nil if not recorded.
+func (s *State) GetPreimage(hash powerplay.Bytes32) ([]byte, error) {
+	return loadPreimage(s.kv, hash)
+}
+
 // NewCheckpoint makes a checkpoint of current state.
 // It returns revision of the checkpoint.
 func (s *State) NewCheckpoint() int {
@@ -398,11 +453,39 @@ func (s *State) Stage() *Stage {
 	if s.err != nil {
 		return &Stage{err: s.err}
 	}
+	if s.newStorageTrie != nil {
+		return &Stage{err: errors.New("state is read-only")}
+	}
+	if s.base != nil {
+		return &Stage{err: errors.New("state is speculative")}
+	}
 	changes := s.changes()
 	if s.err != nil {
 		return &Stage{err: s.err}
 	}
-	return newStage(s.root, s.kv, changes)
+	var preimages map[powerplay.Bytes32][]byte
+	if s.recordPreimages {
+		preimages = s.preimages(changes)
+	}
+	return newStage(s.root, s.kv, changes, preimages)
+}
+
+// collect preimages added, and preimages of hashed keys of tries to be updated.
+func (s *State) preimages(changes map[powerplay.Address]*changedObject) map[powerplay.Bytes32][]byte {
+	preimages := make(map[powerplay.Bytes32][]byte)
+	s.sm.Journal(func(k, v interface{}) bool {
+		if key, ok := k.(preimageKey); ok {
+			preimages[powerplay.Bytes32(key)] = v.([]byte)
+		}
+		return true
+	})
+	for addr, obj := range changes {
+		preimages[powerplay.Blake2b(addr[:])] = addr.Bytes()
+		for key := range obj.storage {
+			preimages[powerplay.Blake2b(key[:])] = key.Bytes()
+		}
+	}
+	return preimages
 }
 
 type (
@@ -411,6 +494,7 @@ type (
 		key  powerplay.Bytes32
 	}
 	codeKey       powerplay.Address
+	preimageKey   powerplay.Bytes32
 	changedObject struct {
 		data    Account
 		storage map[powerplay.Bytes32]rlp.RawValue
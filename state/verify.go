@@ -0,0 +1,84 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/kv"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/trie"
+)
+
+// Verifier checks whether states are fully resolvable, that's all nodes of
+// accounts trie, storage tries and codes are present in kv store.
+// Visited nodes are skipped, so verifying adjacent states is cheap.
+type Verifier struct {
+	kv      kv.GetPutter
+	visited map[powerplay.Bytes32]struct{}
+}
+
+// NewVerifier create a state verifier.
+func NewVerifier(kv kv.GetPutter) *Verifier {
+	return &Verifier{
+		kv,
+		make(map[powerplay.Bytes32]struct{}),
+	}
+}
+
+// Verify walks through the state with given root.
+func (v *Verifier) Verify(root powerplay.Bytes32) error {
+	return v.walk(root, func(leaf []byte) error {
+		var acc Account
+		if err := rlp.DecodeBytes(leaf, &acc); err != nil {
+			return errors.WithMessage(err, "decode account")
+		}
+		if len(acc.CodeHash) > 0 {
+			has, err := v.kv.Has(acc.CodeHash)
+			if err != nil {
+				return err
+			}
+			if !has {
+				return errors.Errorf("code missing: %x", acc.CodeHash)
+			}
+		}
+		if err := v.walk(powerplay.BytesToBytes32(acc.StorageRoot), nil); err != nil {
+			return errors.WithMessage(err, "storage trie")
+		}
+		return nil
+	})
+}
+
+func (v *Verifier) walk(root powerplay.Bytes32, onLeaf func(leaf []byte) error) error {
+	tr, err := trie.NewSecure(root, v.kv, 0)
+	if err != nil {
+		return err
+	}
+	it := tr.NodeIterator(nil)
+	descend := true
+	for it.Next(descend) {
+		descend = true
+		if it.Leaf() {
+			if onLeaf != nil {
+				if err := onLeaf(it.LeafBlob()); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		hash := it.Hash()
+		if hash.IsZero() {
+			// embedded node
+			continue
+		}
+		if _, ok := v.visited[hash]; ok {
+			descend = false
+			continue
+		}
+		v.visited[hash] = struct{}{}
+	}
+	return it.Error()
+}
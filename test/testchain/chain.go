@@ -0,0 +1,73 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package testchain provides an in-memory devnet chain for tests.
+package testchain
+
+import (
+	"testing"
+
+	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/chain"
+	"github.com/playmakerchain/powerplay/genesis"
+	"github.com/playmakerchain/powerplay/lvldb"
+	"github.com/playmakerchain/powerplay/packer"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/state"
+	"github.com/playmakerchain/powerplay/tx"
+)
+
+// Chain a devnet chain backed by memory.
+type Chain struct {
+	*chain.Chain
+	KV           *lvldb.LevelDB
+	StateCreator *state.Creator
+	Genesis      *block.Block
+}
+
+// New create a devnet chain with only the genesis block.
+func New(t *testing.T) *Chain {
+	kv, err := lvldb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateCreator := state.NewCreator(kv)
+	b0, _, err := genesis.NewDevnet().Build(stateCreator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := chain.New(kv, b0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Chain{c, kv, stateCreator, b0}
+}
+
+// PackBlock packs txs into a block upon the parent, at `slots` block intervals after it.
+// The block is signed by the only authority of devnet, and added to the chain.
+func (c *Chain) PackBlock(t *testing.T, parent *block.Header, slots uint64, txs ...*tx.Transaction) *block.Block {
+	acc := genesis.DevAccounts()[0]
+	flow, err := packer.New(c.Chain, c.StateCreator, acc.Address, &acc.Address).
+		Mock(parent, parent.Timestamp()+powerplay.BlockInterval*slots, parent.GasLimit())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, trx := range txs {
+		if err := flow.Adopt(trx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b, stage, receipts, err := flow.Pack(acc.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stage.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.AddBlock(b, receipts); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
@@ -0,0 +1,32 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package tx
+
+// Features bitset contains tx features.
+type Features uint32
+
+const (
+	// DelegationFeature indicates the tx is paid by a designated gas payer (delegator),
+	// whose signature follows the origin's.
+	DelegationFeature Features = 1
+
+	// supportedFeatures all known feature bits, others are reserved.
+	supportedFeatures = DelegationFeature
+)
+
+// IsDelegated returns whether the delegation feature set.
+func (f Features) IsDelegated() bool {
+	return f&DelegationFeature == DelegationFeature
+}
+
+// SetDelegated set delegation feature flag.
+func (f *Features) SetDelegated(flag bool) {
+	if flag {
+		*f |= DelegationFeature
+	} else {
+		*f &= ^DelegationFeature
+	}
+}
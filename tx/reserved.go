@@ -0,0 +1,57 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package tx
+
+import (
+	"errors"
+	"io"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// reserved is the reserved field of tx body.
+// It's encoded as rlp list [features, unused...], with trailing zero values trimmed.
+type reserved struct {
+	Features Features
+	Unused   []rlp.RawValue
+}
+
+// EncodeRLP implements rlp.Encoder.
+func (r reserved) EncodeRLP(w io.Writer) error {
+	if r.Features == 0 && len(r.Unused) == 0 {
+		return rlp.Encode(w, []interface{}{})
+	}
+	list := make([]interface{}, 0, len(r.Unused)+1)
+	list = append(list, r.Features)
+	for _, v := range r.Unused {
+		list = append(list, v)
+	}
+	return rlp.Encode(w, list)
+}
+
+// DecodeRLP implements rlp.Decoder.
+func (r *reserved) DecodeRLP(s *rlp.Stream) error {
+	var raws []rlp.RawValue
+	if err := s.Decode(&raws); err != nil {
+		return err
+	}
+	if len(raws) == 0 {
+		*r = reserved{}
+		return nil
+	}
+
+	// trailing zero values should be trimmed
+	if last := raws[len(raws)-1]; len(last) == 1 && last[0] == 0x80 {
+		return errors.New("rlp: reserved fields not trimmed")
+	}
+
+	var features Features
+	if err := rlp.DecodeBytes(raws[0], &features); err != nil {
+		return err
+	}
+	*r = reserved{features, raws[1:]}
+	return nil
+}
@@ -25,6 +25,7 @@ import (
 
 var (
 	errIntrinsicGasOverflow = errors.New("intrinsic gas overflow")
+	errSignatureLength      = errors.New("invalid signature length")
 )
 
 // Transaction is an immutable tx type.
@@ -34,6 +35,7 @@ type Transaction struct {
 	cache struct {
 		signingHash  atomic.Value
 		signer       atomic.Value
+		delegator    atomic.Value
 		id           atomic.Value
 		unprovedWork atomic.Value
 		size         atomic.Value
@@ -51,7 +53,7 @@ type body struct {
 	Gas          uint64
 	DependsOn    *powerplay.Bytes32 `rlp:"nil"`
 	Nonce        uint64
-	Reserved     []interface{}
+	Reserved     reserved
 	Signature    []byte
 }
 
@@ -131,7 +133,7 @@ func (t *Transaction) EvaluateWork(signer powerplay.Address) func(nonce uint64)
 		t.body.GasPriceCoef,
 		t.body.Gas,
 		t.body.DependsOn,
-		t.body.Reserved,
+		&t.body.Reserved,
 		signer,
 	})
 
@@ -164,7 +166,7 @@ func (t *Transaction) SigningHash() (hash powerplay.Bytes32) {
 		t.body.Gas,
 		t.body.DependsOn,
 		t.body.Nonce,
-		t.body.Reserved,
+		&t.body.Reserved,
 	})
 	hw.Sum(hash[:0])
 	return
@@ -195,6 +197,11 @@ func (t *Transaction) DependsOn() *powerplay.Bytes32 {
 	return &cpy
 }
 
+// Features returns features.
+func (t *Transaction) Features() Features {
+	return t.body.Reserved.Features
+}
+
 // Signature returns signature.
 func (t *Transaction) Signature() []byte {
 	return append([]byte(nil), t.body.Signature...)
@@ -211,7 +218,15 @@ func (t *Transaction) Signer() (signer powerplay.Address, err error) {
 		}
 	}()
 
-	pub, err := crypto.SigToPub(t.SigningHash().Bytes(), t.body.Signature)
+	sig := t.body.Signature
+	if t.Features().IsDelegated() {
+		if len(sig) != 65*2 {
+			return powerplay.Address{}, errSignatureLength
+		}
+		sig = sig[:65]
+	}
+
+	pub, err := crypto.SigToPub(t.SigningHash().Bytes(), sig)
 	if err != nil {
 		return powerplay.Address{}, err
 	}
@@ -219,6 +234,40 @@ func (t *Transaction) Signer() (signer powerplay.Address, err error) {
 	return
 }
 
+// Delegator extract gas payer designated by the signer, from the second half of signature.
+// It returns nil if delegation feature not set.
+func (t *Transaction) Delegator() (delegator *powerplay.Address, err error) {
+	if !t.Features().IsDelegated() {
+		return nil, nil
+	}
+	if cached := t.cache.delegator.Load(); cached != nil {
+		addr := cached.(powerplay.Address)
+		return &addr, nil
+	}
+	defer func() {
+		if err == nil {
+			t.cache.delegator.Store(*delegator)
+		}
+	}()
+
+	signer, err := t.Signer()
+	if err != nil {
+		return nil, err
+	}
+	pub, err := crypto.SigToPub(t.DelegatorSigningHash(signer).Bytes(), t.body.Signature[65:])
+	if err != nil {
+		return nil, err
+	}
+	addr := powerplay.Address(crypto.PubkeyToAddress(*pub))
+	return &addr, nil
+}
+
+// DelegatorSigningHash returns hash of tx for delegator to sign, which binds the tx origin.
+// hash = blake2b(signingHash, origin)
+func (t *Transaction) DelegatorSigningHash(origin powerplay.Address) powerplay.Bytes32 {
+	return powerplay.Blake2b(t.SigningHash().Bytes(), origin.Bytes())
+}
+
 // WithSignature create a new tx with signature set.
 func (t *Transaction) WithSignature(sig []byte) *Transaction {
 	newTx := Transaction{
@@ -229,10 +278,10 @@ func (t *Transaction) WithSignature(sig []byte) *Transaction {
 	return &newTx
 }
 
-// HasReservedFields returns if there're reserved fields.
+// HasReservedFields returns if there're unused reserved fields, or unknown feature bits set.
 // Reserved fields are for backward compatibility purpose.
 func (t *Transaction) HasReservedFields() bool {
-	return len(t.body.Reserved) > 0
+	return len(t.body.Reserved.Unused) > 0 || t.body.Reserved.Features&^supportedFeatures != 0
 }
 
 // EncodeRLP implements rlp.Encoder
@@ -335,6 +384,7 @@ func (t *Transaction) OverallGasPrice(baseGasPrice *big.Int, headBlockNum uint32
 func (t *Transaction) String() string {
 	var (
 		from      string
+		delegator string
 		br        BlockRef
 		dependsOn string
 	)
@@ -344,6 +394,13 @@ func (t *Transaction) String() string {
 	} else {
 		from = signer.String()
 	}
+	if d, err := t.Delegator(); err != nil {
+		delegator = "N/A"
+	} else if d == nil {
+		delegator = "nil"
+	} else {
+		delegator = d.String()
+	}
 
 	binary.BigEndian.PutUint64(br[:], t.body.BlockRef)
 	if t.body.DependsOn == nil {
@@ -355,6 +412,7 @@ func (t *Transaction) String() string {
 	return fmt.Sprintf(`
 	Tx(%v, %v)
 	From:           %v
+	Delegator:      %v
 	Clauses:        %v
 	GasPriceCoef:   %v
 	Gas:            %v
@@ -365,7 +423,7 @@ func (t *Transaction) String() string {
 	Nonce:          %v
 	UnprovedWork:   %v	
 	Signature:      0x%x
-`, t.ID(), t.Size(), from, t.body.Clauses, t.body.GasPriceCoef, t.body.Gas,
+`, t.ID(), t.Size(), from, delegator, t.body.Clauses, t.body.GasPriceCoef, t.body.Gas,
 		t.body.ChainTag, br.Number(), br[4:], t.body.Expiration, dependsOn, t.body.Nonce, t.UnprovedWork(), t.body.Signature)
 }
 
@@ -52,6 +52,50 @@ func TestTx(t *testing.T) {
 	)
 }
 
+func TestDelegatedTx(t *testing.T) {
+	var feat tx.Features
+	feat.SetDelegated(true)
+	trx := new(tx.Builder).ChainTag(1).Gas(21000).Features(feat).Build()
+	assert.True(t, trx.Features().IsDelegated())
+
+	originKey, _ := crypto.GenerateKey()
+	delegatorKey, _ := crypto.GenerateKey()
+	origin := powerplay.Address(crypto.PubkeyToAddress(originKey.PublicKey))
+	delegator := powerplay.Address(crypto.PubkeyToAddress(delegatorKey.PublicKey))
+
+	sig, _ := crypto.Sign(trx.SigningHash().Bytes(), originKey)
+	_, err := trx.WithSignature(sig).Signer()
+	assert.NotNil(t, err, "delegator signature missing")
+
+	delegatorSig, _ := crypto.Sign(trx.DelegatorSigningHash(origin).Bytes(), delegatorKey)
+	trx = trx.WithSignature(append(sig, delegatorSig...))
+
+	signer, err := trx.Signer()
+	assert.Nil(t, err)
+	assert.Equal(t, origin, signer)
+	d, err := trx.Delegator()
+	assert.Nil(t, err)
+	assert.Equal(t, delegator, *d)
+
+	data, _ := rlp.EncodeToBytes(trx)
+	var decoded *tx.Transaction
+	assert.Nil(t, rlp.DecodeBytes(data, &decoded))
+	assert.Equal(t, trx.ID(), decoded.ID())
+	assert.True(t, decoded.Features().IsDelegated())
+	assert.False(t, decoded.HasReservedFields())
+
+	d, err = new(tx.Builder).Build().Delegator()
+	assert.Nil(t, err)
+	assert.Nil(t, d)
+}
+
+func TestUnknownFeatures(t *testing.T) {
+	assert.False(t, new(tx.Builder).Build().HasReservedFields())
+	assert.False(t, new(tx.Builder).Features(tx.DelegationFeature).Build().HasReservedFields())
+	assert.True(t, new(tx.Builder).Features(2).Build().HasReservedFields(), "unknown feature bit")
+	assert.True(t, new(tx.Builder).Features(tx.DelegationFeature|4).Build().HasReservedFields(), "unknown feature bit")
+}
+
 func TestIntrinsicGas(t *testing.T) {
 	gas, err := tx.IntrinsicGas()
 	assert.Nil(t, err)
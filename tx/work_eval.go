@@ -21,7 +21,8 @@ var (
 )
 
 // workToGas exchange proved work to gas.
-// The decay curve follows Moore's law.
+// The decay curve follows Moore's law. Time elapsed is estimated with the default block interval
+// rather than the governed one, as it's consensus relevant and must not change retroactively.
 func workToGas(work *big.Int, blockNum uint32) uint64 {
 	gas := new(big.Int).Div(work, workPerGas)
 	if gas.Sign() == 0 {
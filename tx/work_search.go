@@ -0,0 +1,96 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package tx
+
+import (
+	"context"
+	"math/big"
+	"math/rand"
+	"runtime"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/powerplay"
+)
+
+// number of nonces evaluated between two checks of cancellation
+const searchBatch = 4096
+
+// GasToWork returns the minimum work to be exchanged to the given amount of gas,
+// for txs with block ref of blockNum. It's the inverse of workToGas.
+func GasToWork(gas uint64, blockNum uint32) *big.Int {
+	gasBeforeDecay := new(big.Int).SetUint64(gas)
+
+	months := new(big.Int).SetUint64(uint64(blockNum) * powerplay.BlockInterval / 3600 / 24 / 30)
+	if months.Sign() != 0 {
+		x := &big.Int{}
+		gasBeforeDecay.Mul(gasBeforeDecay, x.Exp(big104, months, nil))
+		// round up
+		d := x.Exp(big100, months, nil)
+		gasBeforeDecay.Add(gasBeforeDecay, new(big.Int).Sub(d, big.NewInt(1)))
+		gasBeforeDecay.Div(gasBeforeDecay, d)
+	}
+	return gasBeforeDecay.Mul(gasBeforeDecay, workPerGas)
+}
+
+// SearchWork searches a nonce, with which the tx signed by signer has work no less than target.
+// The search runs in the given number of goroutines (GOMAXPROCS if not positive),
+// and is stopped when ctx is done.
+func SearchWork(ctx context.Context, tx *Transaction, signer powerplay.Address, target *big.Int, threads int) (nonce uint64, work *big.Int, err error) {
+	if target.Sign() <= 0 {
+		return tx.Nonce(), tx.EvaluateWork(signer)(tx.Nonce()), nil
+	}
+	if target.Cmp(math.MaxBig256) > 0 {
+		return 0, nil, errors.New("target work too large")
+	}
+	if threads <= 0 {
+		threads = runtime.GOMAXPROCS(0)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		nonce uint64
+		work  *big.Int
+	}
+	var (
+		evaluate = tx.EvaluateWork(signer)
+		found    = make(chan result, threads)
+		wg       sync.WaitGroup
+	)
+	for i := 0; i < threads; i++ {
+		wg.Add(1)
+		go func(nonce uint64) {
+			defer wg.Done()
+			for {
+				for j := 0; j < searchBatch; j++ {
+					if work := evaluate(nonce); work.Cmp(target) >= 0 {
+						found <- result{nonce, work}
+						return
+					}
+					nonce++
+				}
+				select {
+				case <-ctx.Done():
+					return
+				default:
+				}
+			}
+		}(rand.Uint64())
+	}
+
+	go func() {
+		wg.Wait()
+		close(found)
+	}()
+
+	if r, ok := <-found; ok {
+		return r.nonce, r.work, nil
+	}
+	return 0, nil, ctx.Err()
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package tx
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGasToWork(t *testing.T) {
+	for _, blockNum := range []uint32{0, 1000000, 10000000} {
+		for _, gas := range []uint64{1, 21000, 1000000} {
+			work := GasToWork(gas, blockNum)
+			assert.Equal(t, gas, workToGas(work, blockNum))
+			assert.True(t, workToGas(new(big.Int).Sub(work, big.NewInt(1)), blockNum) < gas, "should be minimum work")
+		}
+	}
+}
+
+func TestSearchWork(t *testing.T) {
+	trx := new(Builder).ChainTag(1).Gas(21000).Build()
+	signer := powerplay.BytesToAddress([]byte("signer"))
+
+	target := big.NewInt(1000)
+	nonce, work, err := SearchWork(context.Background(), trx, signer, target, 2)
+	assert.Nil(t, err)
+	assert.True(t, work.Cmp(target) >= 0)
+	assert.Equal(t, work, trx.EvaluateWork(signer)(nonce))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, _, err = SearchWork(ctx, trx, signer, math.MaxBig256, 2)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
@@ -6,11 +6,69 @@
 
 package txpool
 
+import "math/big"
+
+// RejectCode identifies the reason why a tx is not accepted by the pool.
+type RejectCode string
+
+// reject codes
+const (
+	RejectInvalid                RejectCode = "invalid"
+	RejectChainTagMismatch       RejectCode = "chain_tag_mismatch"
+	RejectReservedFields         RejectCode = "reserved_fields"
+	RejectSizeLimit              RejectCode = "size_limit"
+	RejectIntrinsicGas           RejectCode = "intrinsic_gas"
+	RejectFeatureNotActivated    RejectCode = "feature_not_activated"
+	RejectGasLimit               RejectCode = "gas_limit"
+	RejectExpired                RejectCode = "expired"
+	RejectBlockRefOutOfSchedule  RejectCode = "block_ref_out_of_schedule"
+	RejectDuplicate              RejectCode = "duplicate"
+	RejectDepReverted            RejectCode = "dep_reverted"
+	RejectInsufficientEnergy     RejectCode = "insufficient_energy"
+	RejectNonExecutable          RejectCode = "non_executable"
+	RejectAccountQuota           RejectCode = "account_quota"
+	RejectReplacementUnderpriced RejectCode = "replacement_underpriced"
+	RejectPoolFull               RejectCode = "pool_full"
+)
+
+// Rejection describes why a tx is not accepted by the pool.
+type Rejection struct {
+	Code    RejectCode
+	Message string
+	// Required and Available are set for shortage rejections, e.g.
+	// energy for insufficient energy, or gas for intrinsic gas.
+	Required  *big.Int
+	Available *big.Int
+}
+
+type rejection struct {
+	code      RejectCode
+	msg       string
+	required  *big.Int
+	available *big.Int
+}
+
+func newRejection(code RejectCode, msg string) rejection {
+	return rejection{code: code, msg: msg}
+}
+
+func (r rejection) Error() string {
+	return r.msg
+}
+
 type (
-	badTxError      struct{ msg string }
-	txRejectedError struct{ msg string }
+	badTxError      struct{ rejection }
+	txRejectedError struct{ rejection }
 )
 
+func newBadTxError(code RejectCode, msg string) badTxError {
+	return badTxError{newRejection(code, msg)}
+}
+
+func newTxRejectedError(code RejectCode, msg string) txRejectedError {
+	return txRejectedError{newRejection(code, msg)}
+}
+
 func (e badTxError) Error() string {
 	return "bad tx: " + e.msg
 }
@@ -30,3 +88,23 @@ func IsTxRejected(err error) bool {
 	_, ok := err.(txRejectedError)
 	return ok
 }
+
+// RejectionOf returns the rejection carried by errors returned by the pool.
+// Nil returned if the error is neither bad tx nor tx rejected.
+func RejectionOf(err error) *Rejection {
+	var r rejection
+	switch e := err.(type) {
+	case badTxError:
+		r = e.rejection
+	case txRejectedError:
+		r = e.rejection
+	default:
+		return nil
+	}
+	return &Rejection{
+		Code:      r.code,
+		Message:   err.Error(),
+		Required:  r.required,
+		Available: r.available,
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package txpool
+
+import (
+	"io"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/tx"
+)
+
+// txJournal is an append-only file of rlp encoded entries, to keep txs of the pool across restarts.
+// Txs removed from the pool are not erased from the file, until it's rotated.
+type txJournal struct {
+	path   string
+	lock   sync.Mutex
+	writer *os.File
+}
+
+// journalEntry a journaled tx along with its local flag.
+type journalEntry struct {
+	Tx    *tx.Transaction
+	Local bool
+}
+
+func newTxJournal(path string) *txJournal {
+	return &txJournal{path: path}
+}
+
+// load decodes entries from the journal file and calls add for each of them.
+// Bare txs written by earlier versions are loaded as non-local.
+// It returns numbers of txs added and dropped (rejected by add).
+func (j *txJournal) load(add func(tx *tx.Transaction, local bool) error) (loaded int, dropped int, err error) {
+	f, err := os.Open(j.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, 0, nil
+		}
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	stream := rlp.NewStream(f, 0)
+	for {
+		var raw rlp.RawValue
+		if err := stream.Decode(&raw); err != nil {
+			if err == io.EOF {
+				return loaded, dropped, nil
+			}
+			// the tail may be corrupted by an unclean shutdown
+			return loaded, dropped, errors.WithMessage(err, "decode")
+		}
+		var entry journalEntry
+		if err := rlp.DecodeBytes(raw, &entry); err != nil {
+			var tx tx.Transaction
+			if err := rlp.DecodeBytes(raw, &tx); err != nil {
+				return loaded, dropped, errors.WithMessage(err, "decode")
+			}
+			entry.Tx = &tx
+		}
+		if err := add(entry.Tx, entry.Local); err != nil {
+			dropped++
+		} else {
+			loaded++
+		}
+	}
+}
+
+// insert appends the tx to the journal file.
+func (j *txJournal) insert(tx *tx.Transaction, local bool) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if j.writer == nil {
+		return errors.New("journal not opened")
+	}
+	return rlp.Encode(j.writer, &journalEntry{tx, local})
+}
+
+// rotate regenerates the journal file with entries returned by getEntries, and reopens it for appending.
+// getEntries is called with the journal locked, so that txs inserted meanwhile are not lost.
+func (j *txJournal) rotate(getEntries func() []*journalEntry) (int, error) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if j.writer != nil {
+		if err := j.writer.Close(); err != nil {
+			return 0, err
+		}
+		j.writer = nil
+	}
+
+	entries := getEntries()
+	tmpPath := j.path + ".new"
+	f, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return 0, err
+	}
+	for _, entry := range entries {
+		if err := rlp.Encode(f, entry); err != nil {
+			f.Close()
+			return 0, err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmpPath, j.path); err != nil {
+		return 0, err
+	}
+
+	writer, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return 0, err
+	}
+	j.writer = writer
+	return len(entries), nil
+}
+
+func (j *txJournal) close() error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if j.writer == nil {
+		return nil
+	}
+	err := j.writer.Close()
+	j.writer = nil
+	return err
+}
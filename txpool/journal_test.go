@@ -0,0 +1,82 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package txpool
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/genesis"
+	"github.com/playmakerchain/powerplay/lvldb"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/state"
+	"github.com/playmakerchain/powerplay/tx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "txpool-journal")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	kv, _ := lvldb.NewMem()
+	chain := newChain(kv)
+	options := Options{
+		Limit:           10,
+		LimitPerAccount: 2,
+		MaxLifetime:     time.Hour,
+		Journal:         filepath.Join(dir, "tx.journal"),
+	}
+
+	pool := New(chain, state.NewCreator(kv), options)
+	tx1 := newTx(chain.Tag(), nil, 21000, tx.BlockRef{}, 100, nil, genesis.DevAccounts()[0])
+	tx2 := newTx(chain.Tag(), nil, 21000, tx.BlockRef{}, 100, nil, genesis.DevAccounts()[1])
+	assert.Nil(t, pool.Add(tx1))
+	assert.Nil(t, pool.Add(tx2))
+	assert.True(t, pool.Remove(tx2.ID()))
+	pool.Close()
+
+	// removed tx is still in journal until rotated, and replayed
+	pool = New(chain, state.NewCreator(kv), options)
+	assert.Equal(t, 2, len(pool.Dump()))
+	pool.Close()
+
+	// local flag is kept across restarts
+	pool = New(chain, state.NewCreator(kv), options)
+	local := newTx(chain.Tag(), nil, 21000, tx.BlockRef{}, 100, nil, genesis.DevAccounts()[3])
+	assert.Nil(t, pool.AddLocal(local))
+	pool.Close()
+
+	pool = New(chain, state.NewCreator(kv), options)
+	txObj := pool.all.Get(local.ID())
+	if assert.NotNil(t, txObj) {
+		assert.True(t, txObj.local)
+	}
+	assert.False(t, pool.all.Get(tx1.ID()).local)
+	pool.Close()
+
+	// expired txs are dropped on rotation
+	b1 := new(block.Builder).
+		ParentID(chain.GenesisBlock().Header().ID()).
+		Timestamp(chain.GenesisBlock().Header().Timestamp() + powerplay.BlockInterval).
+		TotalScore(1).
+		GasLimit(10000000).
+		StateRoot(chain.GenesisBlock().Header().StateRoot()).
+		Build()
+	_, err = chain.AddBlock(b1, nil)
+	assert.Nil(t, err)
+
+	pool = New(chain, state.NewCreator(kv), options)
+	defer pool.Close()
+	expired := newTx(chain.Tag(), nil, 21000, tx.BlockRef{}, 0, nil, genesis.DevAccounts()[2])
+	pool.Fill(tx.Transactions{expired})
+	assert.Equal(t, 4, len(pool.Dump()))
+	assert.Equal(t, 3, len(pool.journalEntries()))
+}
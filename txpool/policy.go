@@ -0,0 +1,62 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package txpool
+
+import (
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/tx"
+)
+
+// Policy customizes ordering and eviction of txs in the pool.
+type Policy interface {
+	// Priority returns priority of the tx. Executable txs are ordered by priority,
+	// and then by overall gas price.
+	Priority(tx *tx.Transaction, origin powerplay.Address, local bool) int
+	// Exempt returns whether the tx is exempt from eviction when pool limit exceeded.
+	Exempt(tx *tx.Transaction, origin powerplay.Address, local bool) bool
+}
+
+// defaultPolicy orders txs by overall gas price only, and exempts nothing.
+type defaultPolicy struct{}
+
+func (defaultPolicy) Priority(*tx.Transaction, powerplay.Address, bool) int { return 0 }
+func (defaultPolicy) Exempt(*tx.Transaction, powerplay.Address, bool) bool  { return false }
+
+// PriorityPolicy prioritizes local txs, txs from whitelisted origins, or txs calling
+// specific contracts. Prioritized txs are also exempt from eviction.
+type PriorityPolicy struct {
+	Locals  bool                       // whether to prioritize txs submitted locally, e.g. via API
+	Origins map[powerplay.Address]bool // whitelisted origins
+	To      map[powerplay.Address]bool // contracts called by any clause of the tx
+}
+
+// Priority implements Policy.
+func (p *PriorityPolicy) Priority(tx *tx.Transaction, origin powerplay.Address, local bool) int {
+	if p.matches(tx, origin, local) {
+		return 1
+	}
+	return 0
+}
+
+// Exempt implements Policy.
+func (p *PriorityPolicy) Exempt(tx *tx.Transaction, origin powerplay.Address, local bool) bool {
+	return p.matches(tx, origin, local)
+}
+
+func (p *PriorityPolicy) matches(tx *tx.Transaction, origin powerplay.Address, local bool) bool {
+	if local && p.Locals {
+		return true
+	}
+	if p.Origins[origin] {
+		return true
+	}
+	for _, clause := range tx.Clauses() {
+		if to := clause.To(); to != nil && p.To[*to] {
+			return true
+		}
+	}
+	return false
+}
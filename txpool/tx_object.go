@@ -11,8 +11,8 @@ import (
 	"sort"
 	"time"
 
-	"github.com/pkg/errors"
 	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/builtin"
 	"github.com/playmakerchain/powerplay/chain"
 	"github.com/playmakerchain/powerplay/runtime"
 	"github.com/playmakerchain/powerplay/state"
@@ -25,10 +25,21 @@ type txObject struct {
 	resolved *runtime.ResolvedTransaction
 
 	timeAdded       int64
+	local           bool // submitted locally, e.g. via API
 	executable      bool
+	priority        int      // assigned by pool policy
 	overallGasPrice *big.Int // don't touch this value, it's only be used in pool's housekeeping
 }
 
+// replacementKey txs with the same key are regarded as versions of one tx,
+// and only one of them is kept in the pool.
+type replacementKey struct {
+	origin    powerplay.Address
+	nonce     uint64
+	blockRef  tx.BlockRef
+	dependsOn powerplay.Bytes32
+}
+
 func resolveTx(tx *tx.Transaction) (*txObject, error) {
 	resolved, err := runtime.ResolveTransaction(tx)
 	if err != nil {
@@ -46,14 +57,43 @@ func (o *txObject) Origin() powerplay.Address {
 	return o.resolved.Origin
 }
 
-func (o *txObject) Executable(chain *chain.Chain, state *state.State, headBlock *block.Header) (bool, error) {
+// replacementKey returns the key of the tx object for replacement.
+func (o *txObject) replacementKey() replacementKey {
+	key := replacementKey{
+		origin:   o.Origin(),
+		nonce:    o.Nonce(),
+		blockRef: o.BlockRef(),
+	}
+	if dep := o.DependsOn(); dep != nil {
+		key.dependsOn = *dep
+	}
+	return key
+}
+
+// CanReplace returns whether the tx object is allowed to replace the other one with the same replacement key.
+// It requires strictly higher gas price coef, or equal coef with strictly higher proved work.
+func (o *txObject) CanReplace(other *txObject) bool {
+	if o.GasPriceCoef() != other.GasPriceCoef() {
+		return o.GasPriceCoef() > other.GasPriceCoef()
+	}
+	// same block ref, so work is proved or not at the same time
+	return o.UnprovedWork().Cmp(other.UnprovedWork()) > 0
+}
+
+// IsCancellation returns whether the tx carries no clause, which cancels the tx it replaces.
+func (o *txObject) IsCancellation() bool {
+	return len(o.Clauses()) == 0
+}
+
+// Executable checks whether the tx is executable in the block after head block, whose interval is blockInterval.
+func (o *txObject) Executable(chain *chain.Chain, state *state.State, headBlock *block.Header, blockInterval uint64) (bool, error) {
 	switch {
 	case o.Gas() > headBlock.GasLimit():
-		return false, errors.New("gas too large")
+		return false, newRejection(RejectGasLimit, "gas too large")
 	case o.IsExpired(headBlock.Number()):
-		return false, errors.New("expired")
-	case o.BlockRef().Number() > headBlock.Number()+uint32(3600*24/powerplay.BlockInterval):
-		return false, errors.New("block ref out of schedule")
+		return false, newRejection(RejectExpired, "expired")
+	case o.BlockRef().Number() > headBlock.Number()+uint32(3600*24/blockInterval):
+		return false, newRejection(RejectBlockRefOutOfSchedule, "block ref out of schedule")
 	}
 
 	if _, err := chain.GetTransactionMeta(o.ID(), headBlock.ID()); err != nil {
@@ -61,7 +101,7 @@ func (o *txObject) Executable(chain *chain.Chain, state *state.State, headBlock
 			return false, err
 		}
 	} else {
-		return false, errors.New("known tx")
+		return false, newRejection(RejectDuplicate, "known tx")
 	}
 
 	if dep := o.DependsOn(); dep != nil {
@@ -73,7 +113,7 @@ func (o *txObject) Executable(chain *chain.Chain, state *state.State, headBlock
 			return false, err
 		}
 		if txMeta.Reverted {
-			return false, errors.New("dep reverted")
+			return false, newRejection(RejectDepReverted, "dep reverted")
 		}
 	}
 
@@ -84,14 +124,33 @@ func (o *txObject) Executable(chain *chain.Chain, state *state.State, headBlock
 	checkpoint := state.NewCheckpoint()
 	defer state.RevertTo(checkpoint)
 
-	if _, _, _, _, err := o.resolved.BuyGas(state, headBlock.Timestamp()+powerplay.BlockInterval); err != nil {
-		return false, err
+	blockTime := headBlock.Timestamp() + blockInterval
+	if _, _, _, _, err := o.resolved.BuyGas(state, blockTime); err != nil {
+		return false, o.insufficientEnergy(state, blockTime, err.Error())
 	}
 	return true, nil
 }
 
-func sortTxObjsByOverallGasPriceDesc(txObjs []*txObject) {
+// insufficientEnergy returns the rejection with energy required by the tx,
+// and energy available to the payer, which is the delegator if any, or the origin.
+func (o *txObject) insufficientEnergy(state *state.State, blockTime uint64, msg string) error {
+	baseGasPrice := builtin.Params.Native(state).Get(powerplay.KeyBaseGasPrice)
+	payer := o.Origin()
+	if o.resolved.Delegator != nil {
+		payer = *o.resolved.Delegator
+	}
+	err := newRejection(RejectInsufficientEnergy, msg)
+	err.required = new(big.Int).Mul(new(big.Int).SetUint64(o.Gas()), o.GasPrice(baseGasPrice))
+	err.available = builtin.Energy.Native(state, blockTime).Get(payer)
+	return err
+}
+
+// sortTxObjsByPriorityDesc sorts tx objects by priority, and then by overall gas price, from high to low.
+func sortTxObjsByPriorityDesc(txObjs []*txObject) {
 	sort.Slice(txObjs, func(i, j int) bool {
+		if txObjs[i].priority != txObjs[j].priority {
+			return txObjs[i].priority > txObjs[j].priority
+		}
 		gp1, gp2 := txObjs[i].overallGasPrice, txObjs[j].overallGasPrice
 		return gp1.Cmp(gp2) >= 0
 	})
@@ -14,16 +14,25 @@ import (
 	"github.com/playmakerchain/powerplay/tx"
 )
 
-// txObjectMap to maintain mapping of ID to tx object, and account quota.
+var (
+	errAccountQuotaExceeded = errors.New("account quota exceeded")
+
+	// ErrReplacementUnderpriced the tx to replace a pending one is not priced higher.
+	ErrReplacementUnderpriced = errors.New("replacement underpriced")
+)
+
+// txObjectMap to maintain mapping of ID to tx object, replacement key to ID, and account quota.
 type txObjectMap struct {
 	lock     sync.RWMutex
 	txObjMap map[powerplay.Bytes32]*txObject
+	keyMap   map[replacementKey]powerplay.Bytes32
 	quota    map[powerplay.Address]int
 }
 
 func newTxObjectMap() *txObjectMap {
 	return &txObjectMap{
 		txObjMap: make(map[powerplay.Bytes32]*txObject),
+		keyMap:   make(map[replacementKey]powerplay.Bytes32),
 		quota:    make(map[powerplay.Address]int),
 	}
 }
@@ -35,21 +44,43 @@ func (m *txObjectMap) Contains(txID powerplay.Bytes32) bool {
 	return found
 }
 
-func (m *txObjectMap) Add(txObj *txObject, limitPerAccount int) error {
+func (m *txObjectMap) Get(txID powerplay.Bytes32) *txObject {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.txObjMap[txID]
+}
+
+// Add adds the tx object. If there is a tx object with the same replacement key,
+// it will be replaced and returned, or an error returned if the new one is not priced higher.
+func (m *txObjectMap) Add(txObj *txObject, limitPerAccount int) (replaced *txObject, err error) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
 	if _, found := m.txObjMap[txObj.ID()]; found {
-		return nil
+		return nil, nil
+	}
+
+	key := txObj.replacementKey()
+	if id, found := m.keyMap[key]; found {
+		replaced = m.txObjMap[id]
+		if !txObj.CanReplace(replaced) {
+			return nil, ErrReplacementUnderpriced
+		}
+		// quota unchanged, since the origin is the same
+		delete(m.txObjMap, id)
+		m.txObjMap[txObj.ID()] = txObj
+		m.keyMap[key] = txObj.ID()
+		return replaced, nil
 	}
 
 	if m.quota[txObj.Origin()] >= limitPerAccount {
-		return errors.New("account quota exceeded")
+		return nil, errAccountQuotaExceeded
 	}
 
 	m.quota[txObj.Origin()]++
 	m.txObjMap[txObj.ID()] = txObj
-	return nil
+	m.keyMap[key] = txObj.ID()
+	return nil, nil
 }
 
 func (m *txObjectMap) Remove(txID powerplay.Bytes32) bool {
@@ -63,6 +94,7 @@ func (m *txObjectMap) Remove(txID powerplay.Bytes32) bool {
 			delete(m.quota, txObj.Origin())
 		}
 		delete(m.txObjMap, txID)
+		delete(m.keyMap, txObj.replacementKey())
 		return true
 	}
 	return false
@@ -97,10 +129,15 @@ func (m *txObjectMap) Fill(txObjs []*txObject) {
 		if _, found := m.txObjMap[txObj.ID()]; found {
 			continue
 		}
+		key := txObj.replacementKey()
+		if _, found := m.keyMap[key]; found {
+			continue
+		}
 		// skip account limit check
 
 		m.quota[txObj.Origin()]++
 		m.txObjMap[txObj.ID()] = txObj
+		m.keyMap[key] = txObj.ID()
 	}
 }
 
@@ -32,14 +32,18 @@ func TestTxObjMap(t *testing.T) {
 	m := newTxObjectMap()
 	assert.Zero(t, m.Len())
 
-	assert.Nil(t, m.Add(txObj1, 1))
-	assert.Nil(t, m.Add(txObj1, 1), "should no error if exists")
+	_, err := m.Add(txObj1, 1)
+	assert.Nil(t, err)
+	_, err = m.Add(txObj1, 1)
+	assert.Nil(t, err, "should no error if exists")
 	assert.Equal(t, 1, m.Len())
 
-	assert.Equal(t, errors.New("account quota exceeded"), m.Add(txObj2, 1))
+	_, err = m.Add(txObj2, 1)
+	assert.Equal(t, errors.New("account quota exceeded"), err)
 	assert.Equal(t, 1, m.Len())
 
-	assert.Nil(t, m.Add(txObj3, 1))
+	_, err = m.Add(txObj3, 1)
+	assert.Nil(t, err)
 	assert.Equal(t, 2, m.Len())
 
 	assert.True(t, m.Contains(tx1.ID()))
@@ -54,3 +58,50 @@ func TestTxObjMap(t *testing.T) {
 	assert.Equal(t, tx.Transactions{tx3}, m.ToTxs())
 
 }
+
+func TestTxObjMapReplace(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	chain := newChain(kv)
+
+	newObj := func(coef uint8, clauses ...*tx.Clause) *txObject {
+		builder := new(tx.Builder).ChainTag(chain.Tag())
+		for _, c := range clauses {
+			builder.Clause(c)
+		}
+		trx := builder.Expiration(100).Nonce(1).GasPriceCoef(coef).Gas(100000).Build()
+		obj, err := resolveTx(signTx(trx, genesis.DevAccounts()[0]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return obj
+	}
+	clause := tx.NewClause(&genesis.DevAccounts()[1].Address)
+
+	txObj1 := newObj(1, clause)
+	underpriced := newObj(0, clause, clause)
+	txObj2 := newObj(2, clause, clause)
+	txObj3 := newObj(3)
+
+	m := newTxObjectMap()
+	_, err := m.Add(txObj1, 1)
+	assert.Nil(t, err)
+
+	_, err = m.Add(underpriced, 1)
+	assert.Equal(t, ErrReplacementUnderpriced, err)
+	assert.True(t, m.Contains(txObj1.ID()))
+
+	replaced, err := m.Add(txObj2, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, txObj1, replaced)
+	assert.Equal(t, []*txObject{txObj2}, m.ToTxObjects())
+
+	replaced, err = m.Add(txObj3, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, txObj2, replaced)
+	assert.True(t, txObj3.IsCancellation())
+	assert.Equal(t, []*txObject{txObj3}, m.ToTxObjects())
+
+	assert.True(t, m.Remove(txObj3.ID()))
+	_, err = m.Add(txObj1, 1)
+	assert.Nil(t, err, "key should be released")
+}
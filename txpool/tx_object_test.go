@@ -57,11 +57,16 @@ func TestSort(t *testing.T) {
 		{overallGasPrice: big.NewInt(20)},
 		{overallGasPrice: big.NewInt(30)},
 	}
-	sortTxObjsByOverallGasPriceDesc(objs)
+	sortTxObjsByPriorityDesc(objs)
 
 	assert.Equal(t, big.NewInt(30), objs[0].overallGasPrice)
 	assert.Equal(t, big.NewInt(20), objs[1].overallGasPrice)
 	assert.Equal(t, big.NewInt(10), objs[2].overallGasPrice)
+
+	objs[2].priority = 1
+	sortTxObjsByPriorityDesc(objs)
+	assert.Equal(t, big.NewInt(10), objs[0].overallGasPrice)
+	assert.Equal(t, big.NewInt(30), objs[1].overallGasPrice)
 }
 
 func TestResolve(t *testing.T) {
@@ -102,7 +107,7 @@ func TestExecutable(t *testing.T) {
 		txObj, err := resolveTx(tt.tx)
 		assert.Nil(t, err)
 
-		exe, err := txObj.Executable(chain, st, b1.Header())
+		exe, err := txObj.Executable(chain, st, b1.Header(), powerplay.BlockInterval)
 		if tt.expectedErr != "" {
 			assert.Equal(t, tt.expectedErr, err.Error())
 		} else {
@@ -7,6 +7,7 @@
 package txpool
 
 import (
+	"math/big"
 	"sync/atomic"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/playmakerchain/powerplay/builtin"
 	"github.com/playmakerchain/powerplay/chain"
 	"github.com/playmakerchain/powerplay/co"
+	"github.com/playmakerchain/powerplay/poa"
 	"github.com/playmakerchain/powerplay/state"
 	"github.com/playmakerchain/powerplay/powerplay"
 	"github.com/playmakerchain/powerplay/tx"
@@ -27,6 +29,8 @@ import (
 const (
 	// max size of tx allowed
 	maxTxSize = 64 * 1024
+	// at most 1/maxExemptRatio of pool limit can be exempted from eviction
+	maxExemptRatio = 4
 )
 
 var (
@@ -38,12 +42,16 @@ type Options struct {
 	Limit           int
 	LimitPerAccount int
 	MaxLifetime     time.Duration
+	Journal         string        // path of journal file, empty to disable journaling
+	JournalRotation time.Duration // interval to regenerate the journal file
+	Policy          Policy        // ordering and eviction policy, nil to order by overall gas price only
 }
 
 // TxEvent will be posted when tx is added or status changed.
 type TxEvent struct {
 	Tx         *tx.Transaction
 	Executable *bool
+	Replaced   *tx.Transaction // the tx evicted by Tx, if any
 }
 
 // TxPool maintains unprocessed transactions.
@@ -51,10 +59,14 @@ type TxPool struct {
 	options      Options
 	chain        *chain.Chain
 	stateCreator *state.Creator
+	forkConfig   powerplay.ForkConfig
 
 	executables    atomic.Value
 	all            *txObjectMap
 	addedAfterWash uint32
+	journal        *txJournal
+	// atomic, block interval in effect after head block, refreshed by housekeeping
+	headBlockInterval uint64
 
 	done   chan struct{}
 	txFeed event.Feed
@@ -65,17 +77,56 @@ type TxPool struct {
 // New create a new TxPool instance.
 // Shutdown is required to be called at end.
 func New(chain *chain.Chain, stateCreator *state.Creator, options Options) *TxPool {
+	if options.Policy == nil {
+		options.Policy = defaultPolicy{}
+	}
 	pool := &TxPool{
 		options:      options,
 		chain:        chain,
 		stateCreator: stateCreator,
+		forkConfig:   powerplay.GetForkConfig(chain.GenesisBlock().Header().ID()),
 		all:          newTxObjectMap(),
 		done:         make(chan struct{}),
 	}
+	pool.headBlockInterval = pool.blockInterval(chain.BestBlock().Header())
+	if options.Journal != "" {
+		pool.loadJournal()
+	}
 	pool.goes.Go(pool.housekeeping)
 	return pool
 }
 
+// loadJournal replays txs in journal, and then regenerates it.
+func (p *TxPool) loadJournal() {
+	journal := newTxJournal(p.options.Journal)
+	loaded, dropped, err := journal.load(func(tx *tx.Transaction, local bool) error {
+		return p.add(tx, false, local)
+	})
+	if err != nil {
+		log.Warn("failed to load tx journal", "err", err)
+	}
+	log.Debug("loaded txs from journal", "loaded", loaded, "dropped", dropped)
+
+	if _, err := journal.rotate(p.journalEntries); err != nil {
+		log.Warn("failed to rotate tx journal", "err", err)
+		return
+	}
+	p.journal = journal
+}
+
+// journalEntries returns txs in the pool to be journaled, with expired ones excluded.
+func (p *TxPool) journalEntries() []*journalEntry {
+	headNum := p.chain.BestBlock().Header().Number()
+	all := p.all.ToTxObjects()
+	entries := make([]*journalEntry, 0, len(all))
+	for _, txObj := range all {
+		if !txObj.IsExpired(headNum) {
+			entries = append(entries, &journalEntry{txObj.Transaction, txObj.local})
+		}
+	}
+	return entries
+}
+
 func (p *TxPool) housekeeping() {
 	log.Debug("enter housekeeping")
 	defer log.Debug("leave housekeeping")
@@ -83,19 +134,33 @@ func (p *TxPool) housekeeping() {
 	ticker := time.NewTicker(time.Second * 2)
 	defer ticker.Stop()
 
+	var rotateC <-chan time.Time
+	if p.journal != nil && p.options.JournalRotation > 0 {
+		rotateTicker := time.NewTicker(p.options.JournalRotation)
+		defer rotateTicker.Stop()
+		rotateC = rotateTicker.C
+	}
+
 	headBlock := p.chain.BestBlock().Header()
 
 	for {
 		select {
 		case <-p.done:
 			return
+		case <-rotateC:
+			if count, err := p.journal.rotate(p.journalEntries); err != nil {
+				log.Warn("failed to rotate tx journal", "err", err)
+			} else {
+				log.Debug("tx journal rotated", "count", count)
+			}
 		case <-ticker.C:
 			var headBlockChanged bool
 			if newHeadBlock := p.chain.BestBlock().Header(); newHeadBlock.ID() != headBlock.ID() {
 				headBlock = newHeadBlock
 				headBlockChanged = true
+				atomic.StoreUint64(&p.headBlockInterval, p.blockInterval(headBlock))
 			}
-			if !isChainSynced(uint64(time.Now().Unix()), headBlock.Timestamp()) {
+			if !isChainSynced(uint64(time.Now().Unix()), headBlock.Timestamp(), atomic.LoadUint64(&p.headBlockInterval)) {
 				// skip washing txs if not synced
 				continue
 			}
@@ -136,6 +201,11 @@ func (p *TxPool) Close() {
 	close(p.done)
 	p.scope.Close()
 	p.goes.Wait()
+	if p.journal != nil {
+		if err := p.journal.close(); err != nil {
+			log.Warn("failed to close tx journal", "err", err)
+		}
+	}
 	log.Debug("closed")
 }
 
@@ -144,7 +214,7 @@ func (p *TxPool) SubscribeTxEvent(ch chan *TxEvent) event.Subscription {
 	return p.scope.Track(p.txFeed.Subscribe(ch))
 }
 
-func (p *TxPool) add(newTx *tx.Transaction, rejectNonexecutable bool) error {
+func (p *TxPool) add(newTx *tx.Transaction, rejectNonexecutable bool, local bool) error {
 	if p.all.Contains(newTx.ID()) {
 		// tx already in the pool
 		return nil
@@ -153,69 +223,132 @@ func (p *TxPool) add(newTx *tx.Transaction, rejectNonexecutable bool) error {
 	// validation
 	switch {
 	case newTx.ChainTag() != p.chain.Tag():
-		return badTxError{"chain tag mismatch"}
+		return newBadTxError(RejectChainTagMismatch, "chain tag mismatch")
 	case newTx.HasReservedFields():
-		return badTxError{"reserved fields not empty"}
+		return newBadTxError(RejectReservedFields, "reserved fields not empty")
 	case newTx.Size() > maxTxSize:
-		return txRejectedError{"size too large"}
+		return newTxRejectedError(RejectSizeLimit, "size too large")
+	case newTx.Features() != 0 && p.chain.BestBlock().Header().Number()+1 < p.forkConfig.FeeDelegation:
+		return newTxRejectedError(RejectFeatureNotActivated, "tx features not activated")
+	}
+
+	intrinsicGas, err := newTx.IntrinsicGas()
+	if err != nil {
+		return newBadTxError(RejectInvalid, err.Error())
+	}
+	if newTx.Gas() < intrinsicGas {
+		err := newBadTxError(RejectIntrinsicGas, "intrinsic gas exceeds provided gas")
+		err.required = new(big.Int).SetUint64(intrinsicGas)
+		err.available = new(big.Int).SetUint64(newTx.Gas())
+		return err
 	}
 
 	txObj, err := resolveTx(newTx)
 	if err != nil {
-		return badTxError{err.Error()}
+		return newBadTxError(RejectInvalid, err.Error())
 	}
+	txObj.local = local
+	txObj.priority = p.options.Policy.Priority(newTx, txObj.Origin(), local)
 
 	headBlock := p.chain.BestBlock().Header()
-	if isChainSynced(uint64(time.Now().Unix()), headBlock.Timestamp()) {
+	if isChainSynced(uint64(time.Now().Unix()), headBlock.Timestamp(), atomic.LoadUint64(&p.headBlockInterval)) {
 		state, err := p.stateCreator.NewState(headBlock.StateRoot())
 		if err != nil {
 			return err
 		}
-
-		executable, err := txObj.Executable(p.chain, state, headBlock)
+		blockInterval := poa.LoadParams(state, p.forkConfig, headBlock.Number()+1).BlockInterval
+		executable, err := txObj.Executable(p.chain, state, headBlock, blockInterval)
 		if err != nil {
-			return txRejectedError{err.Error()}
+			if r, ok := err.(rejection); ok {
+				return txRejectedError{r}
+			}
+			return newTxRejectedError(RejectInvalid, err.Error())
 		}
 
 		if rejectNonexecutable && !executable {
-			return txRejectedError{"tx is not executable"}
+			return newTxRejectedError(RejectNonExecutable, "tx is not executable")
 		}
 
-		if err := p.all.Add(txObj, p.options.LimitPerAccount); err != nil {
-			return txRejectedError{err.Error()}
+		replaced, err := p.all.Add(txObj, p.options.LimitPerAccount)
+		if err != nil {
+			return addingError(err)
 		}
 
 		txObj.executable = executable
+		ev := &TxEvent{Tx: newTx, Executable: &executable}
+		p.onReplaced(ev, txObj, replaced)
 		p.goes.Go(func() {
-			p.txFeed.Send(&TxEvent{newTx, &executable})
+			p.txFeed.Send(ev)
 		})
 		log.Debug("tx added", "id", newTx.ID(), "executable", executable)
 	} else {
 		// we skip steps that rely on head block when chain is not synced,
 		// but check the pool's limit
 		if p.all.Len() >= p.options.Limit {
-			return txRejectedError{"pool is full"}
+			return newTxRejectedError(RejectPoolFull, "pool is full")
 		}
 
-		if err := p.all.Add(txObj, p.options.LimitPerAccount); err != nil {
-			return txRejectedError{err.Error()}
+		replaced, err := p.all.Add(txObj, p.options.LimitPerAccount)
+		if err != nil {
+			return addingError(err)
 		}
+		ev := &TxEvent{Tx: newTx}
+		p.onReplaced(ev, txObj, replaced)
 		log.Debug("tx added", "id", newTx.ID())
-		p.txFeed.Send(&TxEvent{newTx, nil})
+		p.txFeed.Send(ev)
+	}
+	if p.journal != nil {
+		if err := p.journal.insert(newTx, local); err != nil {
+			log.Warn("failed to journal tx", "id", newTx.ID(), "err", err)
+		}
 	}
 	atomic.AddUint32(&p.addedAfterWash, 1)
 	return nil
 }
 
+func (p *TxPool) onReplaced(ev *TxEvent, txObj, replaced *txObject) {
+	if replaced == nil {
+		return
+	}
+	ev.Replaced = replaced.Transaction
+	if txObj.IsCancellation() {
+		log.Debug("tx cancelled", "id", replaced.ID(), "by", txObj.ID())
+	} else {
+		log.Debug("tx replaced", "id", replaced.ID(), "by", txObj.ID())
+	}
+}
+
+// addingError converts errors of txObjectMap.Add into rejections.
+func addingError(err error) error {
+	switch err {
+	case errAccountQuotaExceeded:
+		return newTxRejectedError(RejectAccountQuota, err.Error())
+	case ErrReplacementUnderpriced:
+		return newTxRejectedError(RejectReplacementUnderpriced, err.Error())
+	}
+	return newTxRejectedError(RejectInvalid, err.Error())
+}
+
 // Add add new tx into pool.
+// A pending tx with the same origin, nonce, block ref and depends on will be replaced,
+// if the new one has higher gas price coef or proved work. A replacement without clauses
+// cancels the pending tx.
+// Replacement only takes effect in the local pool. The replaced tx stays valid, since tx ID
+// differs between versions, and may still be packed by other nodes which received it.
 // It's not assumed as an error if the tx to be added is already in the pool,
 func (p *TxPool) Add(newTx *tx.Transaction) error {
-	return p.add(newTx, false)
+	return p.add(newTx, false, false)
+}
+
+// AddLocal add new tx submitted locally into pool, e.g. via API.
+// Local txs may be prioritized according to the pool policy.
+func (p *TxPool) AddLocal(newTx *tx.Transaction) error {
+	return p.add(newTx, false, true)
 }
 
 // StrictlyAdd add new tx into pool. A rejection error will be returned, if tx is not executable at this time.
 func (p *TxPool) StrictlyAdd(newTx *tx.Transaction) error {
-	return p.add(newTx, true)
+	return p.add(newTx, true, false)
 }
 
 // Remove removes tx from pool by its ID.
@@ -227,6 +360,23 @@ func (p *TxPool) Remove(txID powerplay.Bytes32) bool {
 	return false
 }
 
+// Get returns the tx in pool by its ID, or nil if not found.
+func (p *TxPool) Get(txID powerplay.Bytes32) *tx.Transaction {
+	if txObj := p.all.Get(txID); txObj != nil {
+		return txObj.Transaction
+	}
+	return nil
+}
+
+// TimeAdded returns the time when the tx was added into pool, in unix nano.
+// ok is false if the tx is not in pool.
+func (p *TxPool) TimeAdded(txID powerplay.Bytes32) (t int64, ok bool) {
+	if txObj := p.all.Get(txID); txObj != nil {
+		return txObj.timeAdded, true
+	}
+	return 0, false
+}
+
 // Executables returns executable txs.
 func (p *TxPool) Executables() tx.Transactions {
 	if sorted := p.executables.Load(); sorted != nil {
@@ -235,12 +385,14 @@ func (p *TxPool) Executables() tx.Transactions {
 	return nil
 }
 
-// Fill fills txs into pool.
+// Fill fills txs into pool as non-local ones.
+// Txs already in the pool are untouched, so that local flags replayed from journal are kept.
 func (p *TxPool) Fill(txs tx.Transactions) {
 	txObjs := make([]*txObject, 0, len(txs))
 	for _, tx := range txs {
 		// here we ignore errors
 		if txObj, err := resolveTx(tx); err == nil {
+			txObj.priority = p.options.Policy.Priority(tx, txObj.Origin(), false)
 			txObjs = append(txObjs, txObj)
 		}
 	}
@@ -282,6 +434,7 @@ func (p *TxPool) wash(headBlock *block.Header) (executables tx.Transactions, rem
 	var (
 		seeker            = p.chain.NewSeeker(headBlock.ID())
 		baseGasPrice      = builtin.Params.Native(state).Get(powerplay.KeyBaseGasPrice)
+		blockInterval     = poa.LoadParams(state, p.forkConfig, headBlock.Number()+1).BlockInterval
 		executableObjs    = make([]*txObject, 0, len(all))
 		nonExecutableObjs = make([]*txObject, 0, len(all))
 		now               = time.Now().UnixNano()
@@ -294,7 +447,7 @@ func (p *TxPool) wash(headBlock *block.Header) (executables tx.Transactions, rem
 			continue
 		}
 		// settled, out of energy or dep broken
-		executable, err := txObj.Executable(p.chain, state, headBlock)
+		executable, err := txObj.Executable(p.chain, state, headBlock, blockInterval)
 		if err != nil {
 			toRemove = append(toRemove, txObj.ID())
 			log.Debug("tx washed out", "id", txObj.ID(), "err", err)
@@ -320,28 +473,42 @@ func (p *TxPool) wash(headBlock *block.Header) (executables tx.Transactions, rem
 		return nil, 0, errors.WithMessage(err, "seeker")
 	}
 
-	// sort objs by price from high to low
-	sortTxObjsByOverallGasPriceDesc(executableObjs)
-
-	limit := p.options.Limit
-
-	// remove over limit txs, from non-executables to low priced
-	if len(executableObjs) > limit {
-		for _, txObj := range nonExecutableObjs {
-			toRemove = append(toRemove, txObj.ID())
-			log.Debug("non-executable tx washed out due to pool limit", "id", txObj.ID())
-		}
-		for _, txObj := range executableObjs[limit:] {
-			toRemove = append(toRemove, txObj.ID())
-			log.Debug("executable tx washed out due to pool limit", "id", txObj.ID())
+	// sort objs by priority and price from high to low
+	sortTxObjsByPriorityDesc(executableObjs)
+
+	// remove over limit txs, from non-executables to low priced, except exempted ones
+	if excess := len(executableObjs) + len(nonExecutableObjs) - p.options.Limit; excess > 0 {
+		// exemption is capped, so that exempted txs can't occupy the whole pool
+		protected := make(map[*txObject]bool)
+		maxExempt := p.options.Limit / maxExemptRatio
+		for _, objs := range [][]*txObject{executableObjs, nonExecutableObjs} {
+			for _, txObj := range objs {
+				if len(protected) < maxExempt && p.options.Policy.Exempt(txObj.Transaction, txObj.Origin(), txObj.local) {
+					protected[txObj] = true
+				}
+			}
 		}
-		executableObjs = executableObjs[:limit]
-	} else if len(executableObjs)+len(nonExecutableObjs) > limit {
-		// executableObjs + nonExecutableObjs over pool limit
-		for _, txObj := range nonExecutableObjs[limit-len(executableObjs):] {
-			toRemove = append(toRemove, txObj.ID())
-			log.Debug("non-executable tx washed out due to pool limit", "id", txObj.ID())
+
+		evict := func(objs []*txObject, desc string) []*txObject {
+			kept := make([]*txObject, 0, len(objs))
+			for i := len(objs) - 1; i >= 0; i-- {
+				txObj := objs[i]
+				if excess > 0 && !protected[txObj] {
+					excess--
+					toRemove = append(toRemove, txObj.ID())
+					log.Debug(desc+" tx washed out due to pool limit", "id", txObj.ID())
+				} else {
+					kept = append(kept, txObj)
+				}
+			}
+			// restore order
+			for i, j := 0, len(kept)-1; i < j; i, j = i+1, j-1 {
+				kept[i], kept[j] = kept[j], kept[i]
+			}
+			return kept
 		}
+		evict(nonExecutableObjs, "non-executable")
+		executableObjs = evict(executableObjs, "executable")
 	}
 
 	executables = make(tx.Transactions, 0, len(executableObjs))
@@ -358,16 +525,26 @@ func (p *TxPool) wash(headBlock *block.Header) (executables tx.Transactions, rem
 	p.goes.Go(func() {
 		for _, tx := range toBroadcast {
 			executable := true
-			p.txFeed.Send(&TxEvent{tx, &executable})
+			p.txFeed.Send(&TxEvent{Tx: tx, Executable: &executable})
 		}
 	})
 	return executables, 0, nil
 }
 
-func isChainSynced(nowTimestamp, blockTimestamp uint64) bool {
+// blockInterval returns the block interval in effect for the block after head block.
+func (p *TxPool) blockInterval(headBlock *block.Header) uint64 {
+	params, err := poa.LoadParamsAfter(p.stateCreator, p.forkConfig, headBlock)
+	if err != nil {
+		log.Warn("failed to load block interval", "err", err)
+		return poa.DefaultParams.BlockInterval
+	}
+	return params.BlockInterval
+}
+
+func isChainSynced(nowTimestamp, blockTimestamp, blockInterval uint64) bool {
 	timeDiff := nowTimestamp - blockTimestamp
 	if blockTimestamp > nowTimestamp {
 		timeDiff = blockTimestamp - nowTimestamp
 	}
-	return timeDiff < powerplay.BlockInterval*6
+	return timeDiff < blockInterval*6
 }
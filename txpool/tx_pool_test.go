@@ -60,7 +60,7 @@ func TestSubscribeNewTx(t *testing.T) {
 	assert.Nil(t, pool.Add(tx))
 
 	v := true
-	assert.Equal(t, &TxEvent{tx, &v}, <-txCh)
+	assert.Equal(t, &TxEvent{tx, &v, nil}, <-txCh)
 }
 
 func TestWashTxs(t *testing.T) {
@@ -142,3 +142,111 @@ func TestAdd(t *testing.T) {
 		}
 	}
 }
+
+func TestPriorityPolicy(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	chain := newChain(kv)
+	pool := New(chain, state.NewCreator(kv), Options{
+		Limit:           4,
+		LimitPerAccount: 10,
+		MaxLifetime:     time.Hour,
+		Policy:          &PriorityPolicy{Locals: true},
+	})
+	defer pool.Close()
+
+	local := newTx(pool.chain.Tag(), nil, 21000, tx.BlockRef{}, 100, nil, genesis.DevAccounts()[0])
+	assert.Nil(t, pool.AddLocal(local))
+	var remotes Tx.Transactions
+	for _, acc := range genesis.DevAccounts()[1:5] {
+		remotes = append(remotes, newTx(pool.chain.Tag(), nil, 21000, tx.BlockRef{}, 100, nil, acc))
+	}
+	pool.Fill(remotes)
+
+	txs, _, err := pool.wash(pool.chain.BestBlock().Header())
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(txs))
+	assert.Equal(t, local, txs[0], "local tx should be ordered first")
+	assert.Equal(t, 4, len(pool.Dump()))
+	assert.True(t, pool.all.Contains(local.ID()), "local tx should not be evicted")
+}
+
+type exemptAllPolicy struct{ defaultPolicy }
+
+func (exemptAllPolicy) Exempt(*tx.Transaction, powerplay.Address, bool) bool { return true }
+
+func TestExemptCap(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	chain := newChain(kv)
+	pool := New(chain, state.NewCreator(kv), Options{
+		Limit:           4,
+		LimitPerAccount: 10,
+		MaxLifetime:     time.Hour,
+		Policy:          exemptAllPolicy{},
+	})
+	defer pool.Close()
+
+	var txs Tx.Transactions
+	for _, acc := range genesis.DevAccounts()[:5] {
+		txs = append(txs, newTx(pool.chain.Tag(), nil, 21000, tx.BlockRef{}, 100, nil, acc))
+	}
+	pool.Fill(txs)
+
+	_, _, err := pool.wash(pool.chain.BestBlock().Header())
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(pool.Dump()), "exemption should not exceed the cap")
+}
+
+func TestAddReplacement(t *testing.T) {
+	pool := newPool()
+	defer pool.Close()
+
+	b1 := new(block.Builder).
+		ParentID(pool.chain.GenesisBlock().Header().ID()).
+		Timestamp(uint64(time.Now().Unix())).
+		TotalScore(100).
+		GasLimit(10000000).
+		StateRoot(pool.chain.GenesisBlock().Header().StateRoot()).
+		Build()
+	pool.chain.AddBlock(b1, nil)
+
+	txCh := make(chan *TxEvent)
+	pool.SubscribeTxEvent(txCh)
+
+	acc := genesis.DevAccounts()[0]
+	to := acc.Address
+	build := func(gasPriceCoef uint8, clauses ...*tx.Clause) *tx.Transaction {
+		builder := new(tx.Builder).ChainTag(pool.chain.Tag())
+		for _, c := range clauses {
+			builder.Clause(c)
+		}
+		return signTx(builder.Expiration(100).Nonce(1).GasPriceCoef(gasPriceCoef).Gas(21000).Build(), acc)
+	}
+
+	tx1 := build(0, tx.NewClause(&to))
+	assert.Nil(t, pool.Add(tx1))
+	ev := <-txCh
+	assert.Equal(t, tx1, ev.Tx)
+	assert.Nil(t, ev.Replaced)
+
+	// higher coef replaces
+	tx2 := build(10, tx.NewClause(&to))
+	assert.Nil(t, pool.Add(tx2))
+	ev = <-txCh
+	assert.Equal(t, tx2, ev.Tx)
+	assert.Equal(t, tx1, ev.Replaced)
+	assert.Nil(t, pool.Get(tx1.ID()))
+	assert.Equal(t, Tx.Transactions{tx2}, pool.Dump())
+
+	// lower coef is underpriced
+	err := pool.Add(build(5, tx.NewClause(&to)))
+	assert.True(t, IsTxRejected(err))
+	assert.Equal(t, RejectReplacementUnderpriced, RejectionOf(err).Code)
+	assert.Equal(t, Tx.Transactions{tx2}, pool.Dump())
+
+	// no clause cancels
+	tx3 := build(20)
+	assert.Nil(t, pool.Add(tx3))
+	ev = <-txCh
+	assert.Equal(t, tx2, ev.Replaced)
+	assert.Equal(t, Tx.Transactions{tx3}, pool.Dump())
+}
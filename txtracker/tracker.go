@@ -0,0 +1,385 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package txtracker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/playmakerchain/powerplay/block"
+	"github.com/playmakerchain/powerplay/chain"
+	"github.com/playmakerchain/powerplay/co"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/tx"
+	"github.com/playmakerchain/powerplay/txpool"
+)
+
+const (
+	// max count of txs being tracked
+	maxTracked = 10000
+	// how long a tx is kept after it reaches a final status
+	retention = time.Hour
+)
+
+var (
+	log = log15.New("pkg", "txtracker")
+
+	errTooManyTracked = errors.New("too many tracked txs")
+)
+
+// Status lifecycle status of a tracked tx.
+type Status string
+
+// statuses of tracked tx.
+const (
+	StatusUnknown    Status = "unknown"    // not seen in pool or on chain yet
+	StatusPooled     Status = "pooled"     // in pool but not executable, e.g. waiting for the tx it depends on
+	StatusExecutable Status = "executable" // in pool and ready to be packed
+	StatusPacked     Status = "packed"     // packed into a trunk block
+	StatusReverted   Status = "reverted"   // packed into a trunk block, but execution reverted
+	StatusExpired    Status = "expired"    // expired before being packed
+	StatusDropped    Status = "dropped"    // removed from pool before being packed
+	StatusReorgedOut Status = "reorged"    // the block containing it is no longer on trunk
+)
+
+// IsFinal returns whether the status is final. A final status won't change, except that
+// packed or reverted txs may still be reorged out.
+func (s Status) IsFinal() bool {
+	switch s {
+	case StatusPacked, StatusReverted, StatusExpired, StatusDropped:
+		return true
+	}
+	return false
+}
+
+// Change the status of a tracked tx after change.
+type Change struct {
+	TxID       powerplay.Bytes32
+	Status     Status
+	BlockID    *powerplay.Bytes32 // the block containing the tx, for packed, reverted and reorged
+	ReplacedBy *powerplay.Bytes32 // the tx replaced it in pool, for dropped
+}
+
+type trackedTx struct {
+	tx        *tx.Transaction // nil until seen, if tracked by ID
+	change    Change
+	changedAt time.Time
+}
+
+// Tracker follows the lifecycle of registered txs, from pool to chain.
+type Tracker struct {
+	chain *chain.Chain
+	pool  *txpool.TxPool
+	txs   map[powerplay.Bytes32]*trackedTx
+	lock  sync.Mutex
+
+	feed  event.Feed
+	scope event.SubscriptionScope
+	done  chan struct{}
+	goes  co.Goes
+}
+
+// New create a tracker instance and starts following pool and chain.
+func New(chain *chain.Chain, pool *txpool.TxPool) *Tracker {
+	t := &Tracker{
+		chain: chain,
+		pool:  pool,
+		txs:   make(map[powerplay.Bytes32]*trackedTx),
+		done:  make(chan struct{}),
+	}
+
+	txCh := make(chan *txpool.TxEvent, 1000)
+	sub := pool.SubscribeTxEvent(txCh)
+	reader := chain.NewBlockReader(chain.BestBlock().Header().ID())
+	t.goes.Go(func() { t.loop(txCh, sub, reader) })
+	return t
+}
+
+// Close stops tracking.
+func (t *Tracker) Close() {
+	close(t.done)
+	t.scope.Close()
+	t.goes.Wait()
+	log.Debug("closed")
+}
+
+// Subscribe receivers will receive status changes of tracked txs.
+func (t *Tracker) Subscribe(ch chan *Change) event.Subscription {
+	return t.scope.Track(t.feed.Subscribe(ch))
+}
+
+// Track starts tracking the tx by ID, and returns the current status.
+func (t *Tracker) Track(txID powerplay.Bytes32) (*Change, error) {
+	return t.track(txID, nil)
+}
+
+// TrackTx starts tracking the tx, and returns the current status.
+// It's preferred to Track, since expiration can be observed with the tx known.
+func (t *Tracker) TrackTx(trx *tx.Transaction) (*Change, error) {
+	return t.track(trx.ID(), trx)
+}
+
+// Get returns the current status of the tracked tx, or nil if not tracked.
+func (t *Tracker) Get(txID powerplay.Bytes32) *Change {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if tracked, ok := t.txs[txID]; ok {
+		change := tracked.change
+		return &change
+	}
+	return nil
+}
+
+func (t *Tracker) track(txID powerplay.Bytes32, trx *tx.Transaction) (*Change, error) {
+	var changes []*Change
+	defer func() { t.send(changes) }()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	tracked, ok := t.txs[txID]
+	if !ok {
+		if len(t.txs) >= maxTracked {
+			return nil, errTooManyTracked
+		}
+		tracked = &trackedTx{
+			change:    Change{TxID: txID, Status: StatusUnknown},
+			changedAt: time.Now(),
+		}
+		t.txs[txID] = tracked
+	}
+	if tracked.tx == nil {
+		tracked.tx = trx
+	}
+
+	best := t.chain.BestBlock().Header()
+	if change, err := t.check(tracked, best, t.executableSet()); err != nil {
+		return nil, err
+	} else if change != nil {
+		changes = append(changes, change)
+	}
+	change := tracked.change
+	return &change, nil
+}
+
+func (t *Tracker) send(changes []*Change) {
+	for _, change := range changes {
+		t.feed.Send(change)
+	}
+}
+
+func (t *Tracker) loop(txCh chan *txpool.TxEvent, sub event.Subscription, reader chain.BlockReader) {
+	defer sub.Unsubscribe()
+
+	ticker := t.chain.NewTicker()
+	for {
+		select {
+		case <-t.done:
+			return
+		case ev := <-txCh:
+			t.send(t.onTxEvent(ev))
+		case <-ticker.C():
+			changes, err := t.onNewBlocks(reader)
+			if err != nil {
+				log.Warn("failed to process new blocks", "err", err)
+			}
+			t.send(changes)
+		}
+	}
+}
+
+func (t *Tracker) onTxEvent(ev *txpool.TxEvent) (changes []*Change) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if ev.Replaced != nil {
+		if tracked, ok := t.txs[ev.Replaced.ID()]; ok {
+			replacedBy := ev.Tx.ID()
+			if change := tracked.update(Change{TxID: tracked.change.TxID, Status: StatusDropped, ReplacedBy: &replacedBy}); change != nil {
+				changes = append(changes, change)
+			}
+		}
+	}
+
+	tracked, ok := t.txs[ev.Tx.ID()]
+	if !ok {
+		return
+	}
+	if tracked.tx == nil {
+		tracked.tx = ev.Tx
+	}
+	if tracked.change.Status == StatusPacked || tracked.change.Status == StatusReverted {
+		// event delivered late
+		return
+	}
+	status := StatusPooled
+	if ev.Executable != nil && *ev.Executable {
+		status = StatusExecutable
+	}
+	if change := tracked.update(Change{TxID: tracked.change.TxID, Status: status}); change != nil {
+		changes = append(changes, change)
+	}
+	return
+}
+
+func (t *Tracker) onNewBlocks(reader chain.BlockReader) ([]*Change, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var changes []*Change
+	for {
+		blocks, err := reader.Read()
+		if err != nil {
+			return changes, err
+		}
+		if len(blocks) == 0 {
+			break
+		}
+		for _, b := range blocks {
+			blockChanges, err := t.processBlock(b)
+			if err != nil {
+				return changes, err
+			}
+			changes = append(changes, blockChanges...)
+		}
+	}
+
+	best := t.chain.BestBlock().Header()
+	executables := t.executableSet()
+	now := time.Now()
+	for id, tracked := range t.txs {
+		if tracked.change.Status.IsFinal() || tracked.change.Status == StatusUnknown {
+			if now.Sub(tracked.changedAt) > retention {
+				delete(t.txs, id)
+				continue
+			}
+		}
+		change, err := t.check(tracked, best, executables)
+		if err != nil {
+			return changes, err
+		}
+		if change != nil {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+// processBlock updates status of tracked txs in the block, which is new on trunk or reorged out.
+func (t *Tracker) processBlock(b *chain.Block) ([]*Change, error) {
+	var (
+		changes  []*Change
+		receipts tx.Receipts
+		blockID  = b.Header().ID()
+	)
+	for i, trx := range b.Transactions() {
+		tracked, ok := t.txs[trx.ID()]
+		if !ok {
+			continue
+		}
+		if tracked.tx == nil {
+			tracked.tx = trx
+		}
+
+		var newChange Change
+		if b.Obsolete {
+			if tracked.change.BlockID == nil || *tracked.change.BlockID != blockID {
+				continue
+			}
+			newChange = Change{TxID: trx.ID(), Status: StatusReorgedOut, BlockID: &blockID}
+		} else {
+			if receipts == nil {
+				var err error
+				if receipts, err = t.chain.GetBlockReceipts(blockID); err != nil {
+					return nil, err
+				}
+			}
+			status := StatusPacked
+			if receipts[i].Reverted {
+				status = StatusReverted
+			}
+			newChange = Change{TxID: trx.ID(), Status: status, BlockID: &blockID}
+		}
+		if change := tracked.update(newChange); change != nil {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+// check resolves status of the tracked tx, which is not packed, from chain and pool.
+func (t *Tracker) check(tracked *trackedTx, best *block.Header, executables map[powerplay.Bytes32]bool) (*Change, error) {
+	status := tracked.change.Status
+	if status == StatusPacked || status == StatusReverted || status == StatusExpired {
+		// packed ones are handled by processBlock
+		return nil, nil
+	}
+	txID := tracked.change.TxID
+
+	meta, err := t.chain.GetTransactionMeta(txID, best.ID())
+	if err != nil {
+		if !t.chain.IsNotFound(err) {
+			return nil, err
+		}
+	} else {
+		status := StatusPacked
+		if meta.Reverted {
+			status = StatusReverted
+		}
+		blockID := meta.BlockID
+		return tracked.update(Change{TxID: txID, Status: status, BlockID: &blockID}), nil
+	}
+
+	if pooled := t.pool.Get(txID); pooled != nil {
+		if tracked.tx == nil {
+			tracked.tx = pooled
+		}
+		if status == StatusDropped {
+			// re-added
+			status = StatusUnknown
+		}
+		if status == StatusUnknown || status == StatusReorgedOut {
+			status = StatusPooled
+			if executables[txID] {
+				status = StatusExecutable
+			}
+			return tracked.update(Change{TxID: txID, Status: status}), nil
+		}
+		return nil, nil
+	}
+
+	if tracked.tx != nil && tracked.tx.IsExpired(best.Number()) {
+		return tracked.update(Change{TxID: txID, Status: StatusExpired}), nil
+	}
+	if status == StatusPooled || status == StatusExecutable {
+		return tracked.update(Change{TxID: txID, Status: StatusDropped}), nil
+	}
+	return nil, nil
+}
+
+func (t *Tracker) executableSet() map[powerplay.Bytes32]bool {
+	executables := t.pool.Executables()
+	set := make(map[powerplay.Bytes32]bool, len(executables))
+	for _, trx := range executables {
+		set[trx.ID()] = true
+	}
+	return set
+}
+
+// update sets the new status, and returns the change if status changed.
+func (tt *trackedTx) update(change Change) *Change {
+	if tt.change.Status == change.Status {
+		return nil
+	}
+	tt.change = change
+	tt.changedAt = time.Now()
+	c := change
+	return &c
+}
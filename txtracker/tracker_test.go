@@ -0,0 +1,96 @@
+// Copyright (c) 2019 The PlayMaker developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package txtracker
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/inconshreveable/log15"
+	"github.com/playmakerchain/powerplay/genesis"
+	"github.com/playmakerchain/powerplay/powerplay"
+	"github.com/playmakerchain/powerplay/test/testchain"
+	"github.com/playmakerchain/powerplay/tx"
+	"github.com/playmakerchain/powerplay/txpool"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	log15.Root().SetHandler(log15.DiscardHandler())
+}
+
+func newTx(chainTag byte, nonce uint64) *tx.Transaction {
+	to := powerplay.BytesToAddress([]byte("to"))
+	trx := new(tx.Builder).
+		ChainTag(chainTag).
+		Expiration(100).
+		Gas(21000).
+		Nonce(nonce).
+		Clause(tx.NewClause(&to).WithValue(big.NewInt(1))).
+		Build()
+	sig, _ := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+	return trx.WithSignature(sig)
+}
+
+func waitChange(t *testing.T, ch chan *Change, txID powerplay.Bytes32, status Status) *Change {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case change := <-ch:
+			if change.TxID == txID && change.Status == status {
+				return change
+			}
+		case <-timeout:
+			t.Fatalf("timeout waiting for status %v", status)
+		}
+	}
+}
+
+func TestTracker(t *testing.T) {
+	c := testchain.New(t)
+	b0 := c.Genesis
+
+	pool := txpool.New(c.Chain, c.StateCreator, txpool.Options{Limit: 100, LimitPerAccount: 16, MaxLifetime: time.Hour})
+	defer pool.Close()
+	tracker := New(c.Chain, pool)
+	defer tracker.Close()
+
+	ch := make(chan *Change, 10)
+	sub := tracker.Subscribe(ch)
+	defer sub.Unsubscribe()
+
+	tx1 := newTx(c.Tag(), 1)
+	change, err := tracker.TrackTx(tx1)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusUnknown, change.Status)
+	assert.Nil(t, tracker.Get(newTx(c.Tag(), 2).ID()))
+
+	assert.Nil(t, pool.Add(tx1))
+	waitChange(t, ch, tx1.ID(), StatusPooled)
+
+	b1 := c.PackBlock(t, b0.Header(), 1, tx1)
+	change = waitChange(t, ch, tx1.ID(), StatusPacked)
+	assert.Equal(t, b1.Header().ID(), *change.BlockID)
+
+	// reorg out b1 by a heavier branch without tx1
+	b1x := c.PackBlock(t, b0.Header(), 1)
+	c.PackBlock(t, b1x.Header(), 1)
+	change = waitChange(t, ch, tx1.ID(), StatusReorgedOut)
+	assert.Equal(t, b1.Header().ID(), *change.BlockID)
+
+	// tracked by ID, and dropped from pool
+	tx2 := newTx(c.Tag(), 2)
+	assert.Nil(t, pool.Add(tx2))
+	change, err = tracker.Track(tx2.ID())
+	assert.Nil(t, err)
+	assert.Contains(t, []Status{StatusPooled, StatusExecutable}, change.Status)
+
+	pool.Remove(tx2.ID())
+	c.PackBlock(t, c.BestBlock().Header(), 1)
+	waitChange(t, ch, tx2.ID(), StatusDropped)
+}